@@ -9,3 +9,8 @@ type Cache interface {
 	IsExist(key string) bool
 	Delete(key string) error
 }
+
+//Adder 支持原子写入的缓存可实现该接口，Add 仅在key不存在时写入，返回是否写入成功
+type Adder interface {
+	Add(key string, val interface{}, timeout time.Duration) (bool, error)
+}
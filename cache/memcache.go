@@ -51,6 +51,20 @@ func (mem *Memcache) Set(key string, val interface{}, timeout time.Duration) (er
 	return mem.conn.Set(item)
 }
 
+//Add 仅在key不存在时写入
+func (mem *Memcache) Add(key string, val interface{}, timeout time.Duration) (bool, error) {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return false, err
+	}
+	item := &memcache.Item{Key: key, Value: data, Expiration: int32(timeout / time.Second)}
+	err = mem.conn.Add(item)
+	if err == memcache.ErrNotStored {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 //Delete delete value in memcache.
 func (mem *Memcache) Delete(key string) error {
 	return mem.conn.Delete(key)
@@ -26,9 +26,12 @@ func NewMemory() *Memory {
 
 //Get return cached value
 func (mem *Memory) Get(key string) interface{} {
+	mem.Lock()
+	defer mem.Unlock()
+
 	if ret, ok := mem.data[key]; ok {
 		if ret.Expired.Before(time.Now()) {
-			mem.deleteKey(key)
+			delete(mem.data, key)
 			return nil
 		}
 		return ret.Data
@@ -38,9 +41,12 @@ func (mem *Memory) Get(key string) interface{} {
 
 // IsExist check value exists in memcache.
 func (mem *Memory) IsExist(key string) bool {
+	mem.Lock()
+	defer mem.Unlock()
+
 	if ret, ok := mem.data[key]; ok {
 		if ret.Expired.Before(time.Now()) {
-			mem.deleteKey(key)
+			delete(mem.data, key)
 			return false
 		}
 		return true
@@ -60,6 +66,21 @@ func (mem *Memory) Set(key string, val interface{}, timeout time.Duration) (err
 	return nil
 }
 
+//Add 仅在key不存在或已过期时写入
+func (mem *Memory) Add(key string, val interface{}, timeout time.Duration) (bool, error) {
+	mem.Lock()
+	defer mem.Unlock()
+
+	if ret, ok := mem.data[key]; ok && !ret.Expired.Before(time.Now()) {
+		return false, nil
+	}
+	mem.data[key] = &data{
+		Data:    val,
+		Expired: time.Now().Add(timeout),
+	}
+	return true, nil
+}
+
 //Delete delete value in memcache.
 func (mem *Memory) Delete(key string) error {
 	mem.deleteKey(key)
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryAdd(t *testing.T) {
+	mem := NewMemory()
+	added, err := mem.Add("key", "1", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = mem.Add("key", "2", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, added)
+	assert.Equal(t, "1", mem.Get("key"))
+
+	//已过期的key可以重新写入
+	assert.Nil(t, mem.Set("expired", "1", -time.Second))
+	added, err = mem.Add("expired", "2", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, added)
+}
@@ -88,6 +88,22 @@ func (r *Redis) Set(key string, val interface{}, timeout time.Duration) (err err
 	return
 }
 
+//Add 仅在key不存在时写入
+func (r *Redis) Add(key string, val interface{}, timeout time.Duration) (bool, error) {
+	conn := r.conn.Get()
+	defer conn.Close()
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return false, err
+	}
+	reply, err := conn.Do("SET", key, data, "EX", int64(timeout/time.Second), "NX")
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
 //IsExist 判断key是否存在
 func (r *Redis) IsExist(key string) bool {
 	conn := r.conn.Get()
@@ -3,6 +3,7 @@ package credential
 import (
 	"testing"
 
+	"github.com/silenceper/wechat/v2/cache"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/h2non/gock.v1"
 )
@@ -16,3 +17,16 @@ func TestGetTicketFromServer(t *testing.T) {
 	assert.Equal(t, "mock-ticket", ticket.Ticket, "they should be equal")
 	assert.Equal(t, int64(10), ticket.ExpiresIn, "they should be equal")
 }
+
+func TestDefaultTicketByType(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Get("/cgi-bin/ticket/getticket").MatchParam("type", "wx_card").
+		Reply(200).JSON(&ResTicket{Ticket: "mock-card-ticket", ExpiresIn: 7200})
+
+	memCache := cache.NewMemory()
+	ticket, err := NewDefaultTicket("appid", CacheKeyOfficialAccountPrefix, memCache, TicketTypeWxCard).GetTicket("arg-ak")
+	assert.Nil(t, err)
+	assert.Equal(t, "mock-card-ticket", ticket)
+	assert.Equal(t, "mock-card-ticket", memCache.Get(CacheKeyOfficialAccountPrefix+"_wx_card_ticket_appid"))
+	assert.Nil(t, memCache.Get(CacheKeyOfficialAccountPrefix+"_jsapi_ticket_appid"))
+}
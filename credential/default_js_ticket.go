@@ -10,24 +10,35 @@ import (
 	"github.com/silenceper/wechat/v2/util"
 )
 
-//获取ticket的url
-const getTicketURL = "https://api.weixin.qq.com/cgi-bin/ticket/getticket?access_token=%s&type=jsapi"
+const (
+	//获取ticket的url
+	getTicketURL = "https://api.weixin.qq.com/cgi-bin/ticket/getticket?access_token=%s&type=jsapi"
+	//获取指定类型ticket的url
+	getTicketByTypeURL = "https://api.weixin.qq.com/cgi-bin/ticket/getticket?access_token=%s&type=%s"
+)
 
 //DefaultJsTicket 默认获取js ticket方法
 type DefaultJsTicket struct {
 	appID          string
 	cacheKeyPrefix string
 	cache          cache.Cache
+	ticketType     TicketType
 	//jsAPITicket 读写锁 同一个AppID一个
 	jsAPITicketLock *sync.Mutex
 }
 
 //NewDefaultJsTicket new
 func NewDefaultJsTicket(appID string, cacheKeyPrefix string, cache cache.Cache) JsTicketHandle {
+	return NewDefaultTicket(appID, cacheKeyPrefix, cache, TicketTypeJsAPI)
+}
+
+//NewDefaultTicket 获取指定类型的ticket，不同类型的ticket分别缓存
+func NewDefaultTicket(appID string, cacheKeyPrefix string, cache cache.Cache, ticketType TicketType) JsTicketHandle {
 	return &DefaultJsTicket{
 		appID:           appID,
 		cache:           cache,
 		cacheKeyPrefix:  cacheKeyPrefix,
+		ticketType:      ticketType,
 		jsAPITicketLock: new(sync.Mutex),
 	}
 }
@@ -46,14 +57,14 @@ func (js *DefaultJsTicket) GetTicket(accessToken string) (ticketStr string, err
 	defer js.jsAPITicketLock.Unlock()
 
 	//先从cache中取
-	jsAPITicketCacheKey := fmt.Sprintf("%s_jsapi_ticket_%s", js.cacheKeyPrefix, js.appID)
+	jsAPITicketCacheKey := fmt.Sprintf("%s_%s_ticket_%s", js.cacheKeyPrefix, js.ticketType, js.appID)
 	val := js.cache.Get(jsAPITicketCacheKey)
 	if val != nil {
 		ticketStr = val.(string)
 		return
 	}
 	var ticket ResTicket
-	ticket, err = GetTicketFromServer(accessToken)
+	ticket, err = GetTicketFromServerByType(accessToken, js.ticketType)
 	if err != nil {
 		return
 	}
@@ -65,8 +76,16 @@ func (js *DefaultJsTicket) GetTicket(accessToken string) (ticketStr string, err
 
 //GetTicketFromServer 从服务器中获取ticket
 func GetTicketFromServer(accessToken string) (ticket ResTicket, err error) {
+	return getTicketFromServer(fmt.Sprintf(getTicketURL, accessToken))
+}
+
+//GetTicketFromServerByType 从服务器中获取指定类型的ticket
+func GetTicketFromServerByType(accessToken string, ticketType TicketType) (ticket ResTicket, err error) {
+	return getTicketFromServer(fmt.Sprintf(getTicketByTypeURL, accessToken, ticketType))
+}
+
+func getTicketFromServer(url string) (ticket ResTicket, err error) {
 	var response []byte
-	url := fmt.Sprintf(getTicketURL, accessToken)
 	response, err = util.HTTPGet(url)
 	if err != nil {
 		return
@@ -1,5 +1,15 @@
 package credential
 
+//TicketType ticket类型
+type TicketType string
+
+const (
+	//TicketTypeJsAPI 调用jssdk使用的jsapi_ticket
+	TicketTypeJsAPI TicketType = "jsapi"
+	//TicketTypeWxCard 调用卡券相关接口使用的api_ticket
+	TicketTypeWxCard TicketType = "wx_card"
+)
+
 //JsTicketHandle js ticket获取
 type JsTicketHandle interface {
 	//GetTicket 获取ticket
@@ -10,6 +10,7 @@ import (
 	"github.com/silenceper/wechat/v2/miniprogram/qrcode"
 	"github.com/silenceper/wechat/v2/miniprogram/subscribe"
 	"github.com/silenceper/wechat/v2/miniprogram/tcb"
+	"github.com/silenceper/wechat/v2/ocr"
 )
 
 //MiniProgram 微信小程序相关API
@@ -66,3 +67,8 @@ func (miniProgram *MiniProgram) GetTcb() *tcb.Tcb {
 func (miniProgram *MiniProgram) GetSubscribe() *subscribe.Subscribe {
 	return subscribe.NewSubscribe(miniProgram.ctx)
 }
+
+//GetOCR 图像识别及图像处理
+func (miniProgram *MiniProgram) GetOCR() *ocr.OCR {
+	return ocr.NewOCR(miniProgram.ctx)
+}
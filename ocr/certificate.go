@@ -0,0 +1,155 @@
+package ocr
+
+import (
+	"net/url"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+// IDCardMode 身份证识别模式
+type IDCardMode string
+
+const (
+	// IDCardModePhoto 拍照模式
+	IDCardModePhoto IDCardMode = "photo"
+	// IDCardModeScan 扫描模式
+	IDCardModeScan IDCardMode = "scan"
+)
+
+const (
+	// IDCardFront 身份证正面
+	IDCardFront = "Front"
+	// IDCardBack 身份证背面
+	IDCardBack = "Back"
+)
+
+// IDCardResult 身份证识别结果，正面返回姓名等信息，背面只返回有效期
+type IDCardResult struct {
+	Type        string `json:"type"` // Front 或 Back
+	Name        string `json:"name"`
+	ID          string `json:"id"`
+	Addr        string `json:"addr"`
+	Gender      string `json:"gender"`
+	Nationality string `json:"nationality"`
+	ValidDate   string `json:"valid_date"`
+}
+
+// IDCard 身份证识别
+func (ocr *OCR) IDCard(img *Image, mode IDCardMode) (*IDCardResult, error) {
+	var res struct {
+		util.CommonError
+		IDCardResult
+	}
+	if err := ocr.post(idCardURL, url.Values{"type": {string(mode)}}, img, &res, "OCRIDCard"); err != nil {
+		return nil, err
+	}
+	return &res.IDCardResult, nil
+}
+
+// BankCard 银行卡识别，返回卡号
+func (ocr *OCR) BankCard(img *Image) (string, error) {
+	var res struct {
+		util.CommonError
+		Number string `json:"number"`
+	}
+	err := ocr.post(bankCardURL, nil, img, &res, "OCRBankCard")
+	return res.Number, err
+}
+
+// DrivingResult 行驶证识别结果
+type DrivingResult struct {
+	PlateNum          string `json:"plate_num"`
+	VehicleType       string `json:"vehicle_type"`
+	Owner             string `json:"owner"`
+	Addr              string `json:"addr"`
+	UseCharacter      string `json:"use_character"`
+	Model             string `json:"model"`
+	Vin               string `json:"vin"`
+	EngineNum         string `json:"engine_num"`
+	RegisterDate      string `json:"register_date"`
+	IssueDate         string `json:"issue_date"`
+	PlateNumB         string `json:"plate_num_b"`
+	Record            string `json:"record"`
+	PassengersNum     string `json:"passengers_num"`
+	TotalQuality      string `json:"total_quality"`
+	PrepareQuality    string `json:"prepare_quality"`
+	OverallSize       string `json:"overall_size"`
+	CardPositionFront struct {
+		Pos Position `json:"pos"`
+	} `json:"card_position_front"`
+	CardPositionBack struct {
+		Pos Position `json:"pos"`
+	} `json:"card_position_back"`
+	ImgSize ImageSize `json:"img_size"`
+}
+
+// Driving 行驶证识别
+func (ocr *OCR) Driving(img *Image) (*DrivingResult, error) {
+	var res struct {
+		util.CommonError
+		DrivingResult
+	}
+	if err := ocr.post(drivingURL, nil, img, &res, "OCRDriving"); err != nil {
+		return nil, err
+	}
+	return &res.DrivingResult, nil
+}
+
+// DrivingLicenseResult 驾驶证识别结果
+type DrivingLicenseResult struct {
+	IDNum        string `json:"id_num"`
+	Name         string `json:"name"`
+	Sex          string `json:"sex"`
+	Nationality  string `json:"nationality"`
+	Address      string `json:"address"`
+	BirthDate    string `json:"birth_date"`
+	IssueDate    string `json:"issue_date"`
+	CarClass     string `json:"car_class"`
+	ValidFrom    string `json:"valid_from"`
+	ValidTo      string `json:"valid_to"`
+	OfficialSeal string `json:"official_seal"`
+}
+
+// DrivingLicense 驾驶证识别
+func (ocr *OCR) DrivingLicense(img *Image) (*DrivingLicenseResult, error) {
+	var res struct {
+		util.CommonError
+		DrivingLicenseResult
+	}
+	if err := ocr.post(drivingLicenseURL, nil, img, &res, "OCRDrivingLicense"); err != nil {
+		return nil, err
+	}
+	return &res.DrivingLicenseResult, nil
+}
+
+// BizLicenseResult 营业执照识别结果
+type BizLicenseResult struct {
+	RegNum              string `json:"reg_num"` // 注册号
+	Serial              string `json:"serial"`  // 编号
+	LegalRepresentative string `json:"legal_representative"`
+	EnterpriseName      string `json:"enterprise_name"`
+	TypeOfOrganization  string `json:"type_of_organization"` // 组成形式
+	Address             string `json:"address"`
+	TypeOfEnterprise    string `json:"type_of_enterprise"`
+	BusinessScope       string `json:"business_scope"`
+	RegisteredCapital   string `json:"registered_capital"`
+	PaidInCapital       string `json:"paid_in_capital"`
+	ValidPeriod         string `json:"valid_period"`
+	RegisteredDate      string `json:"registered_date"`
+	CertPosition        struct {
+		Pos Position `json:"pos"`
+	} `json:"cert_position"`
+	ImgSize ImageSize `json:"img_size"`
+}
+
+// BizLicense 营业执照识别
+func (ocr *OCR) BizLicense(img *Image) (*BizLicenseResult, error) {
+	var res struct {
+		util.CommonError
+		BizLicenseResult
+	}
+	if err := ocr.post(bizLicenseURL, nil, img, &res, "OCRBizLicense"); err != nil {
+		return nil, err
+	}
+	return &res.BizLicenseResult, nil
+}
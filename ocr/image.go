@@ -0,0 +1,63 @@
+package ocr
+
+import (
+	"github.com/silenceper/wechat/v2/util"
+)
+
+// QRCodeResult 二维码/条码识别结果
+type QRCodeResult struct {
+	CodeResults []struct {
+		TypeName string   `json:"type_name"` // 码的类型，如 QR_CODE、EAN_13
+		Data     string   `json:"data"`
+		Pos      Position `json:"pos"`
+	} `json:"code_results"`
+	ImgSize ImageSize `json:"img_size"`
+}
+
+// QRCode 条码/二维码识别，一张图片最多识别30个码
+func (ocr *OCR) QRCode(img *Image) (*QRCodeResult, error) {
+	var res struct {
+		util.CommonError
+		QRCodeResult
+	}
+	if err := ocr.post(qrCodeURL, nil, img, &res, "ImgQRCode"); err != nil {
+		return nil, err
+	}
+	return &res.QRCodeResult, nil
+}
+
+// CropResult 智能裁剪的候选区域
+type CropResult struct {
+	CropLeft   int `json:"crop_left"`
+	CropTop    int `json:"crop_top"`
+	CropRight  int `json:"crop_right"`
+	CropBottom int `json:"crop_bottom"`
+}
+
+// AICropResult 智能裁剪结果
+type AICropResult struct {
+	Results []CropResult `json:"results"`
+	ImgSize ImageSize    `json:"img_size"`
+}
+
+// AICrop 图片智能裁剪
+func (ocr *OCR) AICrop(img *Image) (*AICropResult, error) {
+	var res struct {
+		util.CommonError
+		AICropResult
+	}
+	if err := ocr.post(aiCropURL, nil, img, &res, "ImgAICrop"); err != nil {
+		return nil, err
+	}
+	return &res.AICropResult, nil
+}
+
+// SuperResolution 图片高清化，返回的 media_id 可通过临时素材接口下载，有效期3天
+func (ocr *OCR) SuperResolution(img *Image) (mediaID string, err error) {
+	var res struct {
+		util.CommonError
+		MediaID string `json:"media_id"`
+	}
+	err = ocr.post(superResolutionURL, nil, img, &res, "ImgSuperResolution")
+	return res.MediaID, err
+}
@@ -0,0 +1,136 @@
+// Package ocr 图像识别及图像处理，公众号与小程序共用
+// 文档：https://developers.weixin.qq.com/doc/offiaccount/Intelligent_Interface/OCR.html
+package ocr
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/silenceper/wechat/v2/credential"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	idCardURL          = "https://api.weixin.qq.com/cv/ocr/idcard"
+	bankCardURL        = "https://api.weixin.qq.com/cv/ocr/bankcard"
+	drivingURL         = "https://api.weixin.qq.com/cv/ocr/driving"
+	drivingLicenseURL  = "https://api.weixin.qq.com/cv/ocr/drivinglicense"
+	bizLicenseURL      = "https://api.weixin.qq.com/cv/ocr/bizlicense"
+	commonURL          = "https://api.weixin.qq.com/cv/ocr/comm"
+	plateNumberURL     = "https://api.weixin.qq.com/cv/ocr/platenum"
+	qrCodeURL          = "https://api.weixin.qq.com/cv/img/qrcode"
+	aiCropURL          = "https://api.weixin.qq.com/cv/img/aicrop"
+	superResolutionURL = "https://api.weixin.qq.com/cv/img/superresolution"
+)
+
+// imageFieldname 上传图片时的表单字段名
+const imageFieldname = "img"
+
+// OCR 图像识别
+type OCR struct {
+	credential.AccessTokenHandle
+}
+
+// NewOCR 实例，公众号与小程序的 access_token 均可使用
+func NewOCR(accessTokenHandle credential.AccessTokenHandle) *OCR {
+	return &OCR{AccessTokenHandle: accessTokenHandle}
+}
+
+// Image 待识别的图片，通过 ImageURL 或 ImageReader 创建
+type Image struct {
+	url      string
+	filename string
+	reader   io.Reader
+}
+
+// ImageURL 使用图片链接，图片需可被公网访问
+func ImageURL(imgURL string) *Image {
+	return &Image{url: imgURL}
+}
+
+// ImageReader 以 multipart 方式上传图片，filename 仅用作表单中的文件名，图片大小不超过2M
+func ImageReader(filename string, reader io.Reader) *Image {
+	return &Image{filename: filename, reader: reader}
+}
+
+// Point 坐标点
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Position 四个顶点的坐标
+type Position struct {
+	LeftTop     Point `json:"left_top"`
+	RightTop    Point `json:"right_top"`
+	RightBottom Point `json:"right_bottom"`
+	LeftBottom  Point `json:"left_bottom"`
+}
+
+// ImageSize 图片尺寸
+type ImageSize struct {
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// post 调用接口，图片链接通过 img_url 参数传递，否则上传图片内容
+func (ocr *OCR) post(apiURL string, query url.Values, img *Image, res interface{}, apiName string) error {
+	if img == nil || (img.url == "" && img.reader == nil) {
+		return fmt.Errorf("%s error : image is empty", apiName)
+	}
+	accessToken, err := ocr.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("access_token", accessToken)
+	if img.reader == nil {
+		query.Set("img_url", img.url)
+	}
+	uri := fmt.Sprintf("%s?%s", apiURL, query.Encode())
+
+	var response []byte
+	if img.reader == nil {
+		response, err = util.HTTPPost(uri, "")
+	} else {
+		response, err = util.PostFileFromReader(imageFieldname, img.filename, uri, img.reader)
+	}
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithError(response, res, apiName)
+}
+
+// CommonResult 通用印刷体识别结果
+type CommonResult struct {
+	Items []struct {
+		Text string   `json:"text"`
+		Pos  Position `json:"pos"`
+	} `json:"items"`
+	ImgSize ImageSize `json:"img_size"`
+}
+
+// Common 通用印刷体识别
+func (ocr *OCR) Common(img *Image) (*CommonResult, error) {
+	var res struct {
+		util.CommonError
+		CommonResult
+	}
+	if err := ocr.post(commonURL, nil, img, &res, "OCRCommon"); err != nil {
+		return nil, err
+	}
+	return &res.CommonResult, nil
+}
+
+// PlateNumber 车牌号识别
+func (ocr *OCR) PlateNumber(img *Image) (string, error) {
+	var res struct {
+		util.CommonError
+		Number string `json:"number"`
+	}
+	err := ocr.post(plateNumberURL, nil, img, &res, "OCRPlateNumber")
+	return res.Number, err
+}
@@ -0,0 +1,65 @@
+package ocr
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken string
+
+func (ak mockAccessToken) GetAccessToken() (string, error) {
+	return string(ak), nil
+}
+
+func TestIDCardByURL(t *testing.T) {
+	defer gock.Off()
+	gock.New(idCardURL).
+		MatchParam("type", "photo").
+		MatchParam("img_url", "https://example.com/front.jpg").
+		MatchParam("access_token", "mock-ak").
+		Reply(200).
+		BodyString(`{"errcode":0,"errmsg":"ok","type":"Front","name":"张三","id":"123456789012345678","addr":"地址","gender":"男","nationality":"汉"}`)
+
+	res, err := NewOCR(mockAccessToken("mock-ak")).IDCard(ImageURL("https://example.com/front.jpg"), IDCardModePhoto)
+	assert.Nil(t, err)
+	assert.Equal(t, IDCardFront, res.Type)
+	assert.Equal(t, "张三", res.Name)
+	assert.Equal(t, "123456789012345678", res.ID)
+}
+
+func TestBizLicenseByReader(t *testing.T) {
+	defer gock.Off()
+	gock.New(bizLicenseURL).
+		MatchParam("access_token", "mock-ak").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			if req.URL.Query().Get("img_url") != "" {
+				return false, nil
+			}
+			file, header, err := req.FormFile(imageFieldname)
+			if err != nil {
+				return false, err
+			}
+			defer file.Close()
+			data, err := ioutil.ReadAll(file)
+			return header.Filename == "license.jpg" && string(data) == "image-bytes", err
+		}).
+		Reply(200).
+		BodyString(`{"errcode":0,"errmsg":"ok","reg_num":"123123","enterprise_name":"某某公司","cert_position":{"pos":{"left_top":{"x":155,"y":191}}},"img_size":{"w":966,"h":728}}`)
+
+	res, err := NewOCR(mockAccessToken("mock-ak")).BizLicense(ImageReader("license.jpg", strings.NewReader("image-bytes")))
+	assert.Nil(t, err)
+	assert.Equal(t, "123123", res.RegNum)
+	assert.Equal(t, "某某公司", res.EnterpriseName)
+	assert.Equal(t, 155, res.CertPosition.Pos.LeftTop.X)
+	assert.Equal(t, 966, res.ImgSize.W)
+}
+
+func TestEmptyImage(t *testing.T) {
+	_, err := NewOCR(mockAccessToken("mock-ak")).BankCard(ImageURL(""))
+	assert.NotNil(t, err)
+}
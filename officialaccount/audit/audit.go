@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Record 一次消息处理的审计记录
+type Record struct {
+	AppID       string        `json:"app_id"`
+	OpenID      string        `json:"open_id"`
+	MsgType     string        `json:"msg_type"`
+	Event       string        `json:"event,omitempty"`
+	MsgID       int64         `json:"msg_id,omitempty"`
+	Content     string        `json:"content,omitempty"`
+	RequestXML  string        `json:"request_xml"`            // 解密后的消息原文
+	ResponseXML string        `json:"response_xml,omitempty"` // 被动回复的消息原文（加密前）
+	ReceivedAt  time.Time     `json:"received_at"`
+	HandleCost  time.Duration `json:"handle_cost"` // 消息处理方法的耗时
+	TotalCost   time.Duration `json:"total_cost"`  // 解析、处理及生成回复的总耗时
+	Error       string        `json:"error,omitempty"`
+}
+
+// Sink 审计记录的接收方
+type Sink interface {
+	Write(record *Record) error
+}
+
+// JSONSink 以 JSON Lines 格式将审计记录写入 io.Writer
+type JSONSink struct {
+	lock    sync.Mutex
+	writer  io.Writer
+	encoder *json.Encoder
+}
+
+// NewJSONSink 实例化
+func NewJSONSink(writer io.Writer) *JSONSink {
+	return &JSONSink{
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+// Write 写入一行审计记录
+func (sink *JSONSink) Write(record *Record) error {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+	return sink.encoder.Encode(record)
+}
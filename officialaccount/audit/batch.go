@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Store 用户提供的审计记录存储
+type Store interface {
+	SaveRecords(records []*Record) error
+}
+
+// BatchSink 将审计记录攒批后写入 Store，达到 batchSize 或每隔 flushInterval 写入一次
+type BatchSink struct {
+	store         Store
+	batchSize     int
+	flushInterval time.Duration
+
+	// OnError 写入Store失败时回调，默认打印错误日志
+	OnError func(records []*Record, err error)
+
+	lock    sync.Mutex
+	records []*Record
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewBatchSink 实例化，flushInterval>0 时启动后台定时写入，使用完毕后需调用 Close
+func NewBatchSink(store Store, batchSize int, flushInterval time.Duration) *BatchSink {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	sink := &BatchSink{
+		store:         store,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+	if flushInterval > 0 {
+		sink.wg.Add(1)
+		go sink.loop()
+	}
+	return sink
+}
+
+// Write 追加审计记录，达到 batchSize 时同步写入 Store
+func (sink *BatchSink) Write(record *Record) error {
+	sink.lock.Lock()
+	sink.records = append(sink.records, record)
+	if len(sink.records) < sink.batchSize {
+		sink.lock.Unlock()
+		return nil
+	}
+	records := sink.records
+	sink.records = nil
+	sink.lock.Unlock()
+	return sink.save(records)
+}
+
+// Flush 立即写入已缓存的审计记录
+func (sink *BatchSink) Flush() error {
+	sink.lock.Lock()
+	records := sink.records
+	sink.records = nil
+	sink.lock.Unlock()
+	if len(records) == 0 {
+		return nil
+	}
+	return sink.save(records)
+}
+
+// Close 停止后台写入并写入剩余的审计记录
+func (sink *BatchSink) Close() error {
+	select {
+	case <-sink.done:
+	default:
+		close(sink.done)
+	}
+	sink.wg.Wait()
+	return sink.Flush()
+}
+
+func (sink *BatchSink) save(records []*Record) error {
+	err := sink.store.SaveRecords(records)
+	if err != nil {
+		if sink.OnError != nil {
+			sink.OnError(records, err)
+		} else {
+			log.Errorf("save audit records failed, count=%d, err=%v", len(records), err)
+		}
+	}
+	return err
+}
+
+func (sink *BatchSink) loop() {
+	defer sink.wg.Done()
+	ticker := time.NewTicker(sink.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = sink.Flush()
+		case <-sink.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore struct {
+	lock    sync.Mutex
+	batches [][]*Record
+	err     error
+}
+
+func (store *memoryStore) SaveRecords(records []*Record) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.batches = append(store.batches, records)
+	return store.err
+}
+
+func (store *memoryStore) batchSizes() []int {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	sizes := make([]int, 0, len(store.batches))
+	for _, batch := range store.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func TestBatchSinkFlushOnSize(t *testing.T) {
+	store := &memoryStore{}
+	sink := NewBatchSink(store, 2, 0)
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, sink.Write(&Record{}))
+	}
+	assert.Equal(t, []int{2, 2}, store.batchSizes())
+
+	//Close 写入剩余的记录，重复调用不会出错
+	assert.Nil(t, sink.Close())
+	assert.Nil(t, sink.Close())
+	assert.Equal(t, []int{2, 2, 1}, store.batchSizes())
+}
+
+func TestBatchSinkFlushOnInterval(t *testing.T) {
+	store := &memoryStore{}
+	sink := NewBatchSink(store, 100, 10*time.Millisecond)
+	defer sink.Close()
+	assert.Nil(t, sink.Write(&Record{}))
+	assert.Eventually(t, func() bool {
+		return len(store.batchSizes()) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int{1}, store.batchSizes())
+}
+
+func TestBatchSinkOnError(t *testing.T) {
+	store := &memoryStore{err: errors.New("store unavailable")}
+	sink := NewBatchSink(store, 1, 0)
+	var failed []*Record
+	sink.OnError = func(records []*Record, err error) {
+		failed = append(failed, records...)
+	}
+	record := &Record{OpenID: "openid"}
+	assert.Equal(t, store.err, sink.Write(record))
+	assert.Equal(t, []*Record{record}, failed)
+}
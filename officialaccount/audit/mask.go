@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	phoneRegexp   = regexp.MustCompile(`1[3-9]\d{9}`)
+	contentRegexp = regexp.MustCompile(`(?s)(<(?:Content|Recognition)>)(.*?)(</(?:Content|Recognition)>)`)
+)
+
+// MaskOption 脱敏配置
+type MaskOption struct {
+	OpenID  bool // 脱敏openID，保留首尾各4位
+	Phone   bool // 脱敏手机号，保留前3位与后4位
+	Content bool // 隐藏用户发送的文本/语音识别内容，仅保留长度
+}
+
+// MaskSink 对审计记录脱敏后再交给下游Sink
+type MaskSink struct {
+	sink   Sink
+	option MaskOption
+}
+
+// NewMaskSink 实例化
+func NewMaskSink(sink Sink, option MaskOption) *MaskSink {
+	return &MaskSink{sink: sink, option: option}
+}
+
+// Write 脱敏并写入
+func (ms *MaskSink) Write(record *Record) error {
+	masked := *record
+	ms.option.Apply(&masked)
+	return ms.sink.Write(&masked)
+}
+
+// Apply 对审计记录进行脱敏
+func (option MaskOption) Apply(record *Record) {
+	if option.Content {
+		record.Content = maskContent(record.Content)
+		record.RequestXML = maskXMLContent(record.RequestXML)
+		//回复内容经常会引用用户发送的文本，同样需要脱敏
+		record.ResponseXML = maskXMLContent(record.ResponseXML)
+	}
+	if option.Phone {
+		record.Content = MaskPhone(record.Content)
+		record.RequestXML = MaskPhone(record.RequestXML)
+		record.ResponseXML = MaskPhone(record.ResponseXML)
+	}
+	if option.OpenID && record.OpenID != "" {
+		masked := MaskOpenID(record.OpenID)
+		record.RequestXML = strings.Replace(record.RequestXML, record.OpenID, masked, -1)
+		record.ResponseXML = strings.Replace(record.ResponseXML, record.OpenID, masked, -1)
+		record.OpenID = masked
+	}
+}
+
+// MaskOpenID 脱敏openID，保留首尾各4位
+func MaskOpenID(openID string) string {
+	if len(openID) <= 8 {
+		return strings.Repeat("*", len(openID))
+	}
+	return openID[:4] + strings.Repeat("*", len(openID)-8) + openID[len(openID)-4:]
+}
+
+// MaskPhone 脱敏文本中的手机号
+func MaskPhone(s string) string {
+	locs := phoneRegexp.FindAllStringIndex(s, -1)
+	if len(locs) == 0 {
+		return s
+	}
+	var builder strings.Builder
+	last := 0
+	for _, loc := range locs {
+		start, end := loc[0], loc[1]
+		//前后紧邻数字的不是手机号
+		if (start > 0 && isDigit(s[start-1])) || (end < len(s) && isDigit(s[end])) {
+			continue
+		}
+		builder.WriteString(s[last : start+3])
+		builder.WriteString("****")
+		last = start + 7
+	}
+	builder.WriteString(s[last:])
+	return builder.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// maskXMLContent 隐藏XML中 Content 与 Recognition 节点的内容
+func maskXMLContent(xml string) string {
+	return contentRegexp.ReplaceAllStringFunc(xml, func(s string) string {
+		parts := contentRegexp.FindStringSubmatch(s)
+		inner := strings.TrimSuffix(strings.TrimPrefix(parts[2], "<![CDATA["), "]]>")
+		return parts[1] + maskContent(inner) + parts[3]
+	})
+}
+
+func maskContent(s string) string {
+	if s == "" {
+		return s
+	}
+	return fmt.Sprintf("[masked:%d]", utf8.RuneCountInString(s))
+}
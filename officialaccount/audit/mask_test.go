@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskOption(t *testing.T) {
+	record := &Record{
+		OpenID:      "oABCD1234567890wxyz",
+		Content:     "my phone is 13800001111,13900002222",
+		RequestXML:  "<xml><FromUserName><![CDATA[oABCD1234567890wxyz]]></FromUserName><Content><![CDATA[my phone is 13800001111,13900002222]]></Content></xml>",
+		ResponseXML: "<xml><ToUserName><![CDATA[oABCD1234567890wxyz]]></ToUserName><Content><![CDATA[you said: my phone]]></Content></xml>",
+	}
+	MaskOption{OpenID: true, Phone: true}.Apply(record)
+	assert.Equal(t, "oABC***********wxyz", record.OpenID)
+	assert.Equal(t, "my phone is 138****1111,139****2222", record.Content)
+	assert.NotContains(t, record.RequestXML, "oABCD1234567890wxyz")
+	assert.NotContains(t, record.RequestXML, "13800001111")
+
+	MaskOption{Content: true}.Apply(record)
+	assert.Equal(t, "[masked:35]", record.Content)
+	assert.Contains(t, record.RequestXML, "<Content>[masked:35]</Content>")
+	assert.Contains(t, record.ResponseXML, "<Content>[masked:18]</Content>")
+	assert.NotContains(t, record.ResponseXML, "you said")
+
+	assert.Equal(t, "123456789012", MaskPhone("123456789012"))
+}
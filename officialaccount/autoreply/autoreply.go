@@ -0,0 +1,358 @@
+package autoreply
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	log "github.com/sirupsen/logrus"
+)
+
+// MatchMode 关键词匹配方式
+type MatchMode string
+
+const (
+	// MatchExact 完全匹配
+	MatchExact MatchMode = "equal"
+	// MatchContains 包含匹配
+	MatchContains MatchMode = "contain"
+	// MatchRegex 正则匹配
+	MatchRegex MatchMode = "regex"
+)
+
+// ReplyMode 规则命中后的回复方式
+type ReplyMode string
+
+const (
+	// ReplyAll 全部回复，多条回复时全部通过客服消息按顺序发送，以保证用户收到的顺序与配置一致
+	ReplyAll ReplyMode = "reply_all"
+	// ReplyRandomOne 随机回复一条
+	ReplyRandomOne ReplyMode = "random_one"
+)
+
+// DefaultMaxSending 默认同时进行的客服消息发送任务上限
+const DefaultMaxSending = 100
+
+// ErrSendingBusy 同时进行的客服消息发送任务已达上限，本次回复被丢弃
+var ErrSendingBusy = errors.New("autoreply too many customer messages sending")
+
+// ReplyType 回复内容类型，与公众平台后台的类型保持一致
+type ReplyType string
+
+const (
+	// ReplyTypeText 文本
+	ReplyTypeText ReplyType = "text"
+	// ReplyTypeImage 图片
+	ReplyTypeImage ReplyType = "img"
+	// ReplyTypeVoice 语音
+	ReplyTypeVoice ReplyType = "voice"
+	// ReplyTypeVideo 视频
+	ReplyTypeVideo ReplyType = "video"
+	// ReplyTypeNews 图文
+	ReplyTypeNews ReplyType = "news"
+)
+
+// Keyword 关键词
+type Keyword struct {
+	MatchMode MatchMode
+	Content   string
+
+	re *regexp.Regexp
+}
+
+// ReplyItem 回复内容
+type ReplyItem struct {
+	Type ReplyType
+	// Content 文本内容，或图片、语音、视频的media_id
+	Content string
+	// Title, Description 视频消息的标题和描述
+	Title       string
+	Description string
+	// ThumbMediaID 视频消息的缩略图media_id，通过客服消息发送视频时必填
+	ThumbMediaID string
+	// Articles 图文消息的文章列表，通过客服消息发送时每篇文章单独发送一条
+	Articles []*message.Article
+}
+
+// Rule 关键词回复规则
+type Rule struct {
+	Name      string
+	Keywords  []*Keyword
+	Replies   []*ReplyItem
+	ReplyMode ReplyMode
+}
+
+// AutoReply 本地自动回复引擎，支持关键词回复、关注回复以及默认回复
+type AutoReply struct {
+	*context.Context
+
+	// Sender 多条回复时发送客服消息的方法，为空时在后台goroutine中调用 SendCustomerMessages，
+	// 同时进行的发送任务不超过 DefaultMaxSending；可替换为自己的任务队列
+	Sender func(openID string, items []*ReplyItem)
+	// OnSendError 默认的后台发送失败时的回调，可用于上报监控
+	OnSendError func(openID string, err error)
+
+	manager          *message.Manager
+	sending          chan struct{}
+	lock             sync.RWMutex
+	rules            []*Rule
+	subscribeReplies []*ReplyItem
+	defaultReplies   []*ReplyItem
+}
+
+// NewAutoReply 实例化
+func NewAutoReply(context *context.Context) *AutoReply {
+	return &AutoReply{
+		Context: context,
+		manager: message.NewMessageManager(context),
+		sending: make(chan struct{}, DefaultMaxSending),
+	}
+}
+
+// AddRule 添加关键词回复规则，按添加顺序匹配
+func (ar *AutoReply) AddRule(rule *Rule) error {
+	if err := rule.compile(); err != nil {
+		return err
+	}
+	ar.lock.Lock()
+	defer ar.lock.Unlock()
+	ar.rules = append(ar.rules, rule)
+	return nil
+}
+
+// compile 编译正则关键词，并校验全部回复模式下的回复内容能否作为客服消息发送
+func (rule *Rule) compile() error {
+	for _, keyword := range rule.Keywords {
+		if keyword.MatchMode != MatchRegex {
+			continue
+		}
+		re, err := regexp.Compile(keyword.Content)
+		if err != nil {
+			return fmt.Errorf("rule %s keyword %s compile error, err=%v", rule.Name, keyword.Content, err)
+		}
+		keyword.re = re
+	}
+	if rule.ReplyMode == ReplyRandomOne || len(rule.Replies) < 2 {
+		return nil
+	}
+	for _, item := range rule.Replies {
+		if err := item.validateCustomerMessage(); err != nil {
+			return fmt.Errorf("rule %s reply of type %s can not be sent as customer msg, err=%v", rule.Name, item.Type, err)
+		}
+	}
+	return nil
+}
+
+// ClearRules 清空关键词回复规则
+func (ar *AutoReply) ClearRules() {
+	ar.lock.Lock()
+	defer ar.lock.Unlock()
+	ar.rules = nil
+}
+
+// SetSubscribeReply 设置被关注回复
+func (ar *AutoReply) SetSubscribeReply(items ...*ReplyItem) {
+	ar.lock.Lock()
+	defer ar.lock.Unlock()
+	ar.subscribeReplies = items
+}
+
+// SetDefaultReply 设置收到消息但未命中关键词时的默认回复
+func (ar *AutoReply) SetDefaultReply(items ...*ReplyItem) {
+	ar.lock.Lock()
+	defer ar.lock.Unlock()
+	ar.defaultReplies = items
+}
+
+// Match 返回消息命中的回复内容，未命中关键词时返回nil
+func (ar *AutoReply) Match(msg message.MixMessage) []*ReplyItem {
+	ar.lock.RLock()
+	defer ar.lock.RUnlock()
+	if msg.MsgType == message.MsgTypeEvent {
+		if msg.Event == message.EventSubscribe {
+			return ar.subscribeReplies
+		}
+		return nil
+	}
+	if msg.MsgType != message.MsgTypeText {
+		return nil
+	}
+	content := strings.TrimSpace(msg.Content)
+	for _, rule := range ar.rules {
+		if !rule.match(content) || len(rule.Replies) == 0 {
+			continue
+		}
+		if rule.ReplyMode == ReplyRandomOne {
+			return []*ReplyItem{rule.Replies[rand.Intn(len(rule.Replies))]}
+		}
+		return rule.Replies
+	}
+	return nil
+}
+
+func (rule *Rule) match(content string) bool {
+	for _, keyword := range rule.Keywords {
+		switch keyword.MatchMode {
+		case MatchContains:
+			if strings.Contains(content, keyword.Content) {
+				return true
+			}
+		case MatchRegex:
+			if keyword.re != nil && keyword.re.MatchString(content) {
+				return true
+			}
+		default:
+			if content == keyword.Content {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Handle 处理消息，命中规则时返回被动回复，多条回复时其余内容通过客服消息发送
+func (ar *AutoReply) Handle(msg message.MixMessage) *message.Reply {
+	return ar.reply(msg, ar.Match(msg))
+}
+
+// Handler 包装用户的消息处理方法，可直接传给 server.SetMessageHandler
+// 优先匹配关键词和关注回复，未命中时交给next处理，next 无回复时使用默认回复
+func (ar *AutoReply) Handler(next func(message.MixMessage) *message.Reply) func(message.MixMessage) *message.Reply {
+	return func(msg message.MixMessage) *message.Reply {
+		if items := ar.Match(msg); len(items) > 0 {
+			return ar.reply(msg, items)
+		}
+		if next != nil {
+			if reply := next(msg); reply != nil {
+				return reply
+			}
+		}
+		if msg.MsgType == message.MsgTypeEvent {
+			return nil
+		}
+		ar.lock.RLock()
+		items := ar.defaultReplies
+		ar.lock.RUnlock()
+		return ar.reply(msg, items)
+	}
+}
+
+// reply 单条回复时使用被动回复；多条回复时被动回复与客服消息的到达顺序无法保证，
+// 因此不做被动回复，全部通过客服消息按顺序发送
+func (ar *AutoReply) reply(msg message.MixMessage, items []*ReplyItem) *message.Reply {
+	if len(items) == 0 {
+		return nil
+	}
+	if len(items) == 1 {
+		return items[0].Reply()
+	}
+	openID := string(msg.FromUserName)
+	if ar.Sender != nil {
+		ar.Sender(openID, items)
+		return nil
+	}
+	select {
+	case ar.sending <- struct{}{}:
+	default:
+		ar.sendError(openID, ErrSendingBusy)
+		return nil
+	}
+	go func() {
+		defer func() { <-ar.sending }()
+		if err := ar.SendCustomerMessages(openID, items); err != nil {
+			ar.sendError(openID, err)
+		}
+	}()
+	return nil
+}
+
+func (ar *AutoReply) sendError(openID string, err error) {
+	log.Errorf("autoreply send customer message failed, openID=%s, err=%v", openID, err)
+	if ar.OnSendError != nil {
+		ar.OnSendError(openID, err)
+	}
+}
+
+// SendCustomerMessages 通过客服消息依次发送回复，某条发送失败时停止发送后续内容
+func (ar *AutoReply) SendCustomerMessages(openID string, items []*ReplyItem) error {
+	for _, item := range items {
+		for _, msg := range item.CustomerMessages(openID) {
+			if err := ar.manager.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Reply 转换为被动回复消息
+func (item *ReplyItem) Reply() *message.Reply {
+	switch item.Type {
+	case ReplyTypeImage:
+		return &message.Reply{MsgType: message.MsgTypeImage, MsgData: message.NewImage(item.Content)}
+	case ReplyTypeVoice:
+		return &message.Reply{MsgType: message.MsgTypeVoice, MsgData: message.NewVoice(item.Content)}
+	case ReplyTypeVideo:
+		return &message.Reply{MsgType: message.MsgTypeVideo, MsgData: message.NewVideo(item.Content, item.Title, item.Description)}
+	case ReplyTypeNews:
+		return &message.Reply{MsgType: message.MsgTypeNews, MsgData: message.NewNews(item.Articles)}
+	default:
+		return &message.Reply{MsgType: message.MsgTypeText, MsgData: message.NewText(item.Content)}
+	}
+}
+
+// CustomerMessages 转换为客服消息，客服图文消息只能包含一篇文章，多篇文章时拆分为多条消息
+func (item *ReplyItem) CustomerMessages(openID string) []*message.CustomerMessage {
+	if item.Type != ReplyTypeNews {
+		return []*message.CustomerMessage{item.customerMessage(openID)}
+	}
+	msgs := make([]*message.CustomerMessage, 0, len(item.Articles))
+	for _, article := range item.Articles {
+		msgs = append(msgs, message.NewCustomerNewsMessage(openID, message.MediaArticles{
+			Title:       article.Title,
+			Description: article.Description,
+			URL:         article.URL,
+			Picurl:      article.PicURL,
+		}))
+	}
+	return msgs
+}
+
+// validateCustomerMessage 校验回复内容能否作为客服消息发送
+func (item *ReplyItem) validateCustomerMessage() error {
+	//校验时只关心消息内容，使用占位的openid
+	msgs := item.CustomerMessages("openid")
+	if len(msgs) == 0 {
+		return fmt.Errorf("news reply has no articles")
+	}
+	for _, msg := range msgs {
+		if err := msg.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (item *ReplyItem) customerMessage(openID string) *message.CustomerMessage {
+	msg := &message.CustomerMessage{ToUser: openID}
+	switch item.Type {
+	case ReplyTypeImage:
+		msg.Msgtype = message.MsgTypeImage
+		msg.Image = &message.MediaResource{MediaID: item.Content}
+	case ReplyTypeVoice:
+		msg.Msgtype = message.MsgTypeVoice
+		msg.Voice = &message.MediaResource{MediaID: item.Content}
+	case ReplyTypeVideo:
+		msg.Msgtype = message.MsgTypeVideo
+		msg.Video = &message.MediaVideo{MediaID: item.Content, ThumbMediaID: item.ThumbMediaID, Title: item.Title, Description: item.Description}
+	default:
+		msg.Msgtype = message.MsgTypeText
+		msg.Text = &message.MediaText{Content: item.Content}
+	}
+	return msg
+}
@@ -0,0 +1,215 @@
+package autoreply
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestMatch(t *testing.T) {
+	ar := NewAutoReply(&context.Context{})
+	assert.Nil(t, ar.AddRule(&Rule{
+		Name:     "exact",
+		Keywords: []*Keyword{{MatchMode: MatchExact, Content: "hello"}},
+		Replies:  []*ReplyItem{{Type: ReplyTypeText, Content: "exact"}},
+	}))
+	assert.Nil(t, ar.AddRule(&Rule{
+		Name:     "regex",
+		Keywords: []*Keyword{{MatchMode: MatchRegex, Content: `^order\d+$`}},
+		Replies:  []*ReplyItem{{Type: ReplyTypeText, Content: "regex"}},
+	}))
+	assert.Nil(t, ar.AddRule(&Rule{
+		Name:     "contains",
+		Keywords: []*Keyword{{MatchMode: MatchContains, Content: "help"}},
+		Replies:  []*ReplyItem{{Type: ReplyTypeText, Content: "contains"}},
+	}))
+	assert.NotNil(t, ar.AddRule(&Rule{Keywords: []*Keyword{{MatchMode: MatchRegex, Content: "("}}}))
+	ar.SetSubscribeReply(&ReplyItem{Type: ReplyTypeText, Content: "welcome"})
+
+	textMsg := func(content string) message.MixMessage {
+		msg := message.MixMessage{Content: content}
+		msg.MsgType = message.MsgTypeText
+		return msg
+	}
+	assert.Equal(t, "exact", ar.Match(textMsg("hello"))[0].Content)
+	assert.Equal(t, "regex", ar.Match(textMsg("order123"))[0].Content)
+	assert.Equal(t, "contains", ar.Match(textMsg("need help"))[0].Content)
+	assert.Nil(t, ar.Match(textMsg("hello world")))
+
+	subscribe := message.MixMessage{Event: message.EventSubscribe}
+	subscribe.MsgType = message.MsgTypeEvent
+	assert.Equal(t, "welcome", ar.Match(subscribe)[0].Content)
+}
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func TestReplyItemCustomerMessagesValidate(t *testing.T) {
+	items := []*ReplyItem{
+		{Type: ReplyTypeText, Content: "hello"},
+		{Type: ReplyTypeImage, Content: "image-media-id"},
+		{Type: ReplyTypeVoice, Content: "voice-media-id"},
+		{Type: ReplyTypeVideo, Content: "video-media-id", ThumbMediaID: "thumb-media-id", Title: "title"},
+		{Type: ReplyTypeNews, Articles: []*message.Article{
+			message.NewArticle("a1", "d1", "pic1", "url1"),
+			message.NewArticle("a2", "d2", "pic2", "url2"),
+		}},
+	}
+	for _, item := range items {
+		msgs := item.CustomerMessages("openid")
+		assert.NotEmpty(t, msgs)
+		for _, msg := range msgs {
+			assert.Nil(t, msg.Validate(), "reply type %s", item.Type)
+		}
+	}
+	assert.Len(t, items[4].CustomerMessages("openid"), 2)
+
+	//缺少缩略图的视频无法通过客服消息发送，全部回复模式下添加规则时即被拒绝
+	ar := NewAutoReply(&context.Context{})
+	assert.NotNil(t, ar.AddRule(&Rule{
+		Name:      "video",
+		ReplyMode: ReplyAll,
+		Keywords:  []*Keyword{{MatchMode: MatchExact, Content: "video"}},
+		Replies: []*ReplyItem{
+			{Type: ReplyTypeText, Content: "first"},
+			{Type: ReplyTypeVideo, Content: "video-media-id"},
+		},
+	}))
+}
+
+func TestReplyAllOrder(t *testing.T) {
+	defer gock.Off()
+	var sent []string
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/message/custom/send").Times(4).
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			var msg message.CustomerMessage
+			if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+				return false, err
+			}
+			if msg.Text != nil {
+				sent = append(sent, msg.Text.Content)
+			} else {
+				sent = append(sent, msg.News.Articles[0].Title)
+			}
+			return true, nil
+		}).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	ar := NewAutoReply(&context.Context{AccessTokenHandle: mockAccessToken{}})
+	items := []*ReplyItem{
+		{Type: ReplyTypeText, Content: "first"},
+		{Type: ReplyTypeNews, Articles: []*message.Article{
+			message.NewArticle("second", "", "", ""),
+			message.NewArticle("third", "", "", ""),
+		}},
+		{Type: ReplyTypeText, Content: "fourth"},
+	}
+	msg := message.MixMessage{}
+	msg.FromUserName = "openid"
+	//单条回复使用被动回复，多条回复全部通过客服消息按顺序发送
+	assert.Nil(t, ar.reply(msg, items[:0]))
+	assert.NotNil(t, ar.reply(msg, items[:1]))
+
+	assert.Nil(t, ar.SendCustomerMessages("openid", items))
+	assert.Equal(t, []string{"first", "second", "third", "fourth"}, sent)
+	assert.True(t, gock.IsDone())
+}
+
+func TestReplyMultiItemsSender(t *testing.T) {
+	ar := NewAutoReply(&context.Context{AccessTokenHandle: mockAccessToken{}})
+	var sentTo string
+	var sentItems []*ReplyItem
+	ar.Sender = func(openID string, items []*ReplyItem) {
+		sentTo = openID
+		sentItems = items
+	}
+	items := []*ReplyItem{{Type: ReplyTypeText, Content: "first"}, {Type: ReplyTypeText, Content: "second"}}
+	msg := message.MixMessage{}
+	msg.FromUserName = "openid"
+	assert.Nil(t, ar.reply(msg, items))
+	assert.Equal(t, "openid", sentTo)
+	assert.Equal(t, items, sentItems)
+}
+
+func TestReplyMultiItemsSendError(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/message/custom/send").
+		Reply(200).JSON(map[string]interface{}{"errcode": 45015, "errmsg": "response out of time limit"})
+
+	ar := NewAutoReply(&context.Context{AccessTokenHandle: mockAccessToken{}})
+	errs := make(chan error, 1)
+	ar.OnSendError = func(openID string, err error) {
+		errs <- err
+	}
+	items := []*ReplyItem{{Type: ReplyTypeText, Content: "first"}, {Type: ReplyTypeText, Content: "second"}}
+	msg := message.MixMessage{}
+	msg.FromUserName = "openid"
+	assert.Nil(t, ar.reply(msg, items))
+	select {
+	case err := <-errs:
+		assert.NotNil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("OnSendError was not called")
+	}
+	assert.True(t, gock.IsDone())
+
+	//发送任务已达上限时直接丢弃并回调
+	for i := 0; i < DefaultMaxSending; i++ {
+		ar.sending <- struct{}{}
+	}
+	assert.Nil(t, ar.reply(msg, items))
+	assert.Equal(t, ErrSendingBusy, <-errs)
+}
+
+func TestImportConsoleInfo(t *testing.T) {
+	ar := NewAutoReply(&context.Context{})
+	assert.Nil(t, ar.AddRule(&Rule{
+		Name:     "old",
+		Keywords: []*Keyword{{MatchMode: MatchExact, Content: "old"}},
+		Replies:  []*ReplyItem{{Type: ReplyTypeText, Content: "old"}},
+	}))
+
+	textMsg := func(content string) message.MixMessage {
+		msg := message.MixMessage{Content: content}
+		msg.MsgType = message.MsgTypeText
+		return msg
+	}
+
+	info := &ConsoleAutoReplyInfo{IsAutoreplyOpen: 1}
+	info.KeywordAutoreplyInfo.List = []*ConsoleRule{
+		{
+			RuleName:        "bad regex",
+			KeywordListInfo: []*ConsoleKeyword{{MatchMode: MatchRegex, Content: "("}},
+			ReplyListInfo:   []*ConsoleReplyInfo{{Type: ReplyTypeText, Content: "bad"}},
+		},
+		{
+			RuleName:        "empty news",
+			ReplyMode:       ReplyAll,
+			KeywordListInfo: []*ConsoleKeyword{{MatchMode: MatchExact, Content: "digest"}},
+			ReplyListInfo:   []*ConsoleReplyInfo{{Type: ReplyTypeText, Content: "digest"}, {Type: ReplyTypeNews}},
+		},
+		{
+			RuleName:        "new",
+			KeywordListInfo: []*ConsoleKeyword{{MatchMode: MatchContains, Content: "new"}},
+			ReplyListInfo:   []*ConsoleReplyInfo{{Type: ReplyTypeText, Content: "new"}},
+		},
+	}
+	//无法使用的规则被跳过，不影响其他规则的导入
+	skipped := ar.ImportConsoleInfo(info)
+	assert.Len(t, skipped, 2)
+	assert.Equal(t, "bad regex", skipped[0].Name)
+	assert.Equal(t, "empty news", skipped[1].Name)
+	assert.NotNil(t, skipped[1].Err)
+	assert.Nil(t, ar.Match(textMsg("old")))
+	assert.Nil(t, ar.Match(textMsg("digest")))
+	assert.Equal(t, "new", ar.Match(textMsg("brand new"))[0].Content)
+}
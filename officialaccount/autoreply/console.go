@@ -0,0 +1,159 @@
+package autoreply
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	//获取公众号的自动回复规则
+	//https://developers.weixin.qq.com/doc/offiaccount/Message_Management/Getting_Rules_for_Auto_Replies.html
+	getCurrentAutoReplyInfoURL = "https://api.weixin.qq.com/cgi-bin/get_current_autoreply_info"
+)
+
+// ConsoleReplyInfo 公众平台后台配置的回复内容
+type ConsoleReplyInfo struct {
+	Type     ReplyType `json:"type"`
+	Content  string    `json:"content"`
+	NewsInfo struct {
+		List []*ConsoleNews `json:"list"`
+	} `json:"news_info"`
+}
+
+// ConsoleNews 公众平台后台配置的图文
+type ConsoleNews struct {
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	Digest     string `json:"digest"`
+	ShowCover  int    `json:"show_cover"`
+	CoverURL   string `json:"cover_url"`
+	ContentURL string `json:"content_url"`
+	SourceURL  string `json:"source_url"`
+}
+
+// ConsoleKeyword 公众平台后台配置的关键词
+type ConsoleKeyword struct {
+	Type      string    `json:"type"`
+	MatchMode MatchMode `json:"match_mode"`
+	Content   string    `json:"content"`
+}
+
+// ConsoleRule 公众平台后台配置的关键词规则
+type ConsoleRule struct {
+	RuleName        string              `json:"rule_name"`
+	CreateTime      int64               `json:"create_time"`
+	ReplyMode       ReplyMode           `json:"reply_mode"`
+	KeywordListInfo []*ConsoleKeyword   `json:"keyword_list_info"`
+	ReplyListInfo   []*ConsoleReplyInfo `json:"reply_list_info"`
+}
+
+// ConsoleAutoReplyInfo 公众平台后台的自动回复配置
+type ConsoleAutoReplyInfo struct {
+	util.CommonError
+
+	IsAddFriendReplyOpen        int               `json:"is_add_friend_reply_open"`
+	IsAutoreplyOpen             int               `json:"is_autoreply_open"`
+	AddFriendAutoreplyInfo      *ConsoleReplyInfo `json:"add_friend_autoreply_info"`
+	MessageDefaultAutoreplyInfo *ConsoleReplyInfo `json:"message_default_autoreply_info"`
+	KeywordAutoreplyInfo        struct {
+		List []*ConsoleRule `json:"list"`
+	} `json:"keyword_autoreply_info"`
+}
+
+// GetConsoleAutoReplyInfo 获取公众平台后台配置的自动回复规则
+func (ar *AutoReply) GetConsoleAutoReplyInfo() (*ConsoleAutoReplyInfo, error) {
+	accessToken, err := ar.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", getCurrentAutoReplyInfoURL, accessToken)
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	info := &ConsoleAutoReplyInfo{}
+	err = util.DecodeWithError(response, info, "GetCurrentAutoReplyInfo")
+	return info, err
+}
+
+// SkippedRule 导入时因无法在本地使用而被跳过的后台规则
+type SkippedRule struct {
+	Name string
+	Err  error
+}
+
+// ImportFromConsole 导入公众平台后台配置的自动回复规则，将替换当前的全部规则，返回被跳过的规则
+// 开启开发者模式后后台配置的自动回复会失效，导入后由本地引擎继续生效
+func (ar *AutoReply) ImportFromConsole() ([]*SkippedRule, error) {
+	info, err := ar.GetConsoleAutoReplyInfo()
+	if err != nil {
+		return nil, err
+	}
+	return ar.ImportConsoleInfo(info), nil
+}
+
+// ImportConsoleInfo 导入已获取的后台自动回复配置，新规则全部构建完成后一次性替换当前规则，
+// 无法使用的规则（如正则无法编译、全部回复模式下无法作为客服消息发送）会被跳过并返回，不影响其他规则
+func (ar *AutoReply) ImportConsoleInfo(info *ConsoleAutoReplyInfo) []*SkippedRule {
+	var subscribeReplies, defaultReplies []*ReplyItem
+	var rules []*Rule
+	var skipped []*SkippedRule
+	if info.IsAddFriendReplyOpen == 1 && info.AddFriendAutoreplyInfo != nil {
+		subscribeReplies = []*ReplyItem{info.AddFriendAutoreplyInfo.toReplyItem()}
+	}
+	if info.IsAutoreplyOpen == 1 {
+		if info.MessageDefaultAutoreplyInfo != nil {
+			defaultReplies = []*ReplyItem{info.MessageDefaultAutoreplyInfo.toReplyItem()}
+		}
+		rules, skipped = info.rules()
+	}
+
+	ar.lock.Lock()
+	defer ar.lock.Unlock()
+	ar.rules = rules
+	ar.subscribeReplies = subscribeReplies
+	ar.defaultReplies = defaultReplies
+	return skipped
+}
+
+// rules 将后台的关键词规则转换为本地规则
+func (info *ConsoleAutoReplyInfo) rules() ([]*Rule, []*SkippedRule) {
+	rules := make([]*Rule, 0, len(info.KeywordAutoreplyInfo.List))
+	var skipped []*SkippedRule
+	for _, consoleRule := range info.KeywordAutoreplyInfo.List {
+		rule := &Rule{
+			Name:      consoleRule.RuleName,
+			ReplyMode: consoleRule.ReplyMode,
+		}
+		for _, keyword := range consoleRule.KeywordListInfo {
+			rule.Keywords = append(rule.Keywords, &Keyword{MatchMode: keyword.MatchMode, Content: keyword.Content})
+		}
+		for _, replyInfo := range consoleRule.ReplyListInfo {
+			rule.Replies = append(rule.Replies, replyInfo.toReplyItem())
+		}
+		if err := rule.compile(); err != nil {
+			skipped = append(skipped, &SkippedRule{Name: rule.Name, Err: err})
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, skipped
+}
+
+func (info *ConsoleReplyInfo) toReplyItem() *ReplyItem {
+	switch info.Type {
+	case ReplyTypeNews:
+		item := &ReplyItem{Type: ReplyTypeNews}
+		for _, news := range info.NewsInfo.List {
+			item.Articles = append(item.Articles, message.NewArticle(news.Title, news.Digest, news.CoverURL, news.ContentURL))
+		}
+		return item
+	case ReplyTypeVideo:
+		//后台配置的视频回复content为视频链接，无法作为被动回复，以文本链接的方式回复
+		return &ReplyItem{Type: ReplyTypeText, Content: info.Content}
+	default:
+		return &ReplyItem{Type: info.Type, Content: info.Content}
+	}
+}
@@ -0,0 +1,109 @@
+package basic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	//短key托管
+	//文档：https://developers.weixin.qq.com/doc/offiaccount/Account_Management/KEY_Shortener.html
+	genShortKeyURL   = "https://api.weixin.qq.com/cgi-bin/shorten/gen?access_token=%s"
+	fetchShortKeyURL = "https://api.weixin.qq.com/cgi-bin/shorten/fetch?access_token=%s"
+
+	// MaxShortKeyExpire 短key的最长有效期
+	MaxShortKeyExpire = 30 * 24 * time.Hour
+
+	// qrSubscribeScenePrefix 未关注用户扫码关注后，事件EventKey的前缀
+	qrSubscribeScenePrefix = "qrscene_"
+)
+
+// ShortKeyInfo 短key对应的长信息
+type ShortKeyInfo struct {
+	util.CommonError
+	LongData      string `json:"long_data"`
+	CreateTime    int64  `json:"create_time"`
+	ExpireSeconds int64  `json:"expire_seconds"` // 剩余的有效时间，单位秒
+}
+
+// GenShortKey 将不超过4KB的长信息转换为短key，expire 为有效期，<=0 或超过30天时使用最长有效期30天
+func (basic *Basic) GenShortKey(longData string, expire time.Duration) (string, error) {
+	if expire <= 0 || expire > MaxShortKeyExpire {
+		expire = MaxShortKeyExpire
+	}
+	ak, err := basic.GetAccessToken()
+	if err != nil {
+		return "", err
+	}
+	req := map[string]interface{}{
+		"long_data":      longData,
+		"expire_seconds": int64(expire.Seconds()),
+	}
+	data, err := util.PostJSON(fmt.Sprintf(genShortKeyURL, ak), req)
+	if err != nil {
+		return "", err
+	}
+	var res struct {
+		util.CommonError
+		ShortKey string `json:"short_key"`
+	}
+	if err = util.DecodeWithError(data, &res, "GenShortKey"); err != nil {
+		return "", err
+	}
+	return res.ShortKey, nil
+}
+
+// FetchShortKey 通过短key获取长信息
+func (basic *Basic) FetchShortKey(shortKey string) (*ShortKeyInfo, error) {
+	ak, err := basic.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	data, err := util.PostJSON(fmt.Sprintf(fetchShortKeyURL, ak), map[string]string{
+		"short_key": shortKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := new(ShortKeyInfo)
+	if err = util.DecodeWithError(data, res, "FetchShortKey"); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// NewTmpQrPayloadRequest 新建携带任意数据的临时二维码请求实例
+// payload 以JSON格式托管为短key并作为scene_str，短key与二维码的有效期一致，扫码后通过 DecodeScenePayload 取回
+// 短key最长有效期为30天，因此只支持临时二维码，exp 需在 (0, MaxShortKeyExpire] 之间
+func (basic *Basic) NewTmpQrPayloadRequest(exp time.Duration, payload interface{}) (*Request, error) {
+	if exp <= 0 || exp > MaxShortKeyExpire {
+		return nil, fmt.Errorf("qr payload expire must be in (0, %v], got %v", MaxShortKeyExpire, exp)
+	}
+	shortKey, err := basic.genPayloadShortKey(payload, exp)
+	if err != nil {
+		return nil, err
+	}
+	return NewTmpQrRequest(exp, shortKey), nil
+}
+
+func (basic *Basic) genPayloadShortKey(payload interface{}, expire time.Duration) (string, error) {
+	longData, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return basic.GenShortKey(string(longData), expire)
+}
+
+// DecodeScenePayload 从扫码/关注事件的EventKey中取回 NewTmpQrPayloadRequest 等托管的payload并解析到v
+func (basic *Basic) DecodeScenePayload(eventKey string, v interface{}) error {
+	shortKey := strings.TrimPrefix(eventKey, qrSubscribeScenePrefix)
+	info, err := basic.FetchShortKey(shortKey)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(info.LongData), v)
+}
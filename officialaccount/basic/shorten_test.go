@@ -0,0 +1,79 @@
+package basic
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func TestGenShortKeyExpire(t *testing.T) {
+	defer gock.Off()
+	var expireSeconds []int64
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/shorten/gen").Times(2).
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			var body struct {
+				ExpireSeconds int64 `json:"expire_seconds"`
+			}
+			err := json.NewDecoder(req.Body).Decode(&body)
+			expireSeconds = append(expireSeconds, body.ExpireSeconds)
+			return err == nil, err
+		}).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "short_key": "iTaiuvKmXk"})
+
+	basic := NewBasic(&context.Context{AccessTokenHandle: mockAccessToken{}})
+	shortKey, err := basic.GenShortKey("long data", time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, "iTaiuvKmXk", shortKey)
+	_, err = basic.GenShortKey("long data", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3600, int64(MaxShortKeyExpire.Seconds())}, expireSeconds)
+}
+
+func TestTmpQrPayloadRequest(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/shorten/gen").
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "short_key": "iTaiuvKmXk"})
+
+	basic := NewBasic(&context.Context{AccessTokenHandle: mockAccessToken{}})
+	req, err := basic.NewTmpQrPayloadRequest(time.Hour, map[string]int{"order": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, actionStr, req.ActionName)
+	assert.Equal(t, "iTaiuvKmXk", req.ActionInfo.Scene.SceneStr)
+	assert.Equal(t, int64(3600), req.ExpireSeconds)
+
+	//短key无法长期有效，超出有效期范围时直接报错
+	_, err = basic.NewTmpQrPayloadRequest(0, "payload")
+	assert.NotNil(t, err)
+	_, err = basic.NewTmpQrPayloadRequest(MaxShortKeyExpire+time.Second, "payload")
+	assert.NotNil(t, err)
+}
+
+func TestDecodeScenePayload(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/shorten/fetch").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			var body map[string]string
+			err := json.NewDecoder(req.Body).Decode(&body)
+			//关注事件的EventKey带有qrscene_前缀
+			return body["short_key"] == "iTaiuvKmXk", err
+		}).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "long_data": `{"order":1}`, "expire_seconds": 600})
+
+	var payload struct {
+		Order int `json:"order"`
+	}
+	basic := NewBasic(&context.Context{AccessTokenHandle: mockAccessToken{}})
+	assert.Nil(t, basic.DecodeScenePayload("qrscene_iTaiuvKmXk", &payload))
+	assert.Equal(t, 1, payload.Order)
+}
@@ -1,6 +1,7 @@
 package broadcast
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/silenceper/wechat/v2/officialaccount/context"
@@ -11,6 +12,10 @@ const (
 	sendURLByTag    = "https://api.weixin.qq.com/cgi-bin/message/mass/sendall"
 	sendURLByOpenID = "https://api.weixin.qq.com/cgi-bin/message/mass/send"
 	deleteSendURL   = "https://api.weixin.qq.com/cgi-bin/message/mass/delete"
+	previewSendURL  = "https://api.weixin.qq.com/cgi-bin/message/mass/preview"
+	getSendURL      = "https://api.weixin.qq.com/cgi-bin/message/mass/get"
+	getSpeedURL     = "https://api.weixin.qq.com/cgi-bin/message/mass/speed/get"
+	setSpeedURL     = "https://api.weixin.qq.com/cgi-bin/message/mass/speed/set"
 )
 
 //MsgType 发送消息类型
@@ -68,10 +73,14 @@ type sendRequest struct {
 	Voice map[string]interface{} `json:"voice,omitempty"`
 	//发送图片
 	Images *Image `json:"images,omitempty"`
+	//发送视频
+	Mpvideo map[string]interface{} `json:"mpvideo,omitempty"`
 	//发送卡券
 	WxCard            map[string]interface{} `json:"wxcard,omitempty"`
 	MsgType           MsgType                `json:"msgtype"`
 	SendIgnoreReprint int32                  `json:"send_ignore_reprint,omitempty"`
+	//开发者侧群发msgid，用于避免重试时重复群发
+	ClientMsgID string `json:"clientmsgid,omitempty"`
 }
 
 //Image 发送图片
@@ -86,7 +95,8 @@ type Image struct {
 //user 为nil，表示全员发送
 //&User{TagID:2} 根据tag发送
 //&User{OpenID:[]string("xxx","xxx")} 根据openid发送
-func (broadcast *Broadcast) SendText(user *User, content string) (*Result, error) {
+//clientMsgID 可选，为开发者侧群发msgid，24小时内相同的clientmsgid不会重复群发，各发送方法均支持
+func (broadcast *Broadcast) SendText(user *User, content string, clientMsgID ...string) (*Result, error) {
 	ak, err := broadcast.GetAccessToken()
 	if err != nil {
 		return nil, err
@@ -98,6 +108,7 @@ func (broadcast *Broadcast) SendText(user *User, content string) (*Result, error
 	req.Text = map[string]interface{}{
 		"content": content,
 	}
+	req.ClientMsgID = firstClientMsgID(clientMsgID)
 	req, sendURL := broadcast.chooseTagOrOpenID(user, req)
 	url := fmt.Sprintf("%s?access_token=%s", sendURL, ak)
 	data, err := util.PostJSON(url, req)
@@ -110,7 +121,7 @@ func (broadcast *Broadcast) SendText(user *User, content string) (*Result, error
 }
 
 //SendNews 发送图文
-func (broadcast *Broadcast) SendNews(user *User, mediaID string, ignoreReprint bool) (*Result, error) {
+func (broadcast *Broadcast) SendNews(user *User, mediaID string, ignoreReprint bool, clientMsgID ...string) (*Result, error) {
 	ak, err := broadcast.GetAccessToken()
 	if err != nil {
 		return nil, err
@@ -125,6 +136,7 @@ func (broadcast *Broadcast) SendNews(user *User, mediaID string, ignoreReprint b
 	req.Mpnews = map[string]interface{}{
 		"media_id": mediaID,
 	}
+	req.ClientMsgID = firstClientMsgID(clientMsgID)
 	req, sendURL := broadcast.chooseTagOrOpenID(user, req)
 	url := fmt.Sprintf("%s?access_token=%s", sendURL, ak)
 	data, err := util.PostJSON(url, req)
@@ -137,7 +149,7 @@ func (broadcast *Broadcast) SendNews(user *User, mediaID string, ignoreReprint b
 }
 
 //SendVoice 发送语音
-func (broadcast *Broadcast) SendVoice(user *User, mediaID string) (*Result, error) {
+func (broadcast *Broadcast) SendVoice(user *User, mediaID string, clientMsgID ...string) (*Result, error) {
 	ak, err := broadcast.GetAccessToken()
 	if err != nil {
 		return nil, err
@@ -149,6 +161,7 @@ func (broadcast *Broadcast) SendVoice(user *User, mediaID string) (*Result, erro
 	req.Voice = map[string]interface{}{
 		"media_id": mediaID,
 	}
+	req.ClientMsgID = firstClientMsgID(clientMsgID)
 	req, sendURL := broadcast.chooseTagOrOpenID(user, req)
 	url := fmt.Sprintf("%s?access_token=%s", sendURL, ak)
 	data, err := util.PostJSON(url, req)
@@ -161,7 +174,7 @@ func (broadcast *Broadcast) SendVoice(user *User, mediaID string) (*Result, erro
 }
 
 //SendImage 发送图片
-func (broadcast *Broadcast) SendImage(user *User, images *Image) (*Result, error) {
+func (broadcast *Broadcast) SendImage(user *User, images *Image, clientMsgID ...string) (*Result, error) {
 	ak, err := broadcast.GetAccessToken()
 	if err != nil {
 		return nil, err
@@ -171,6 +184,7 @@ func (broadcast *Broadcast) SendImage(user *User, images *Image) (*Result, error
 		MsgType: MsgTypeImage,
 	}
 	req.Images = images
+	req.ClientMsgID = firstClientMsgID(clientMsgID)
 	req, sendURL := broadcast.chooseTagOrOpenID(user, req)
 	url := fmt.Sprintf("%s?access_token=%s", sendURL, ak)
 	data, err := util.PostJSON(url, req)
@@ -183,7 +197,7 @@ func (broadcast *Broadcast) SendImage(user *User, images *Image) (*Result, error
 }
 
 //SendVideo 发送视频
-func (broadcast *Broadcast) SendVideo(user *User, mediaID string, title, description string) (*Result, error) {
+func (broadcast *Broadcast) SendVideo(user *User, mediaID string, title, description string, clientMsgID ...string) (*Result, error) {
 	ak, err := broadcast.GetAccessToken()
 	if err != nil {
 		return nil, err
@@ -192,11 +206,12 @@ func (broadcast *Broadcast) SendVideo(user *User, mediaID string, title, descrip
 		ToUser:  nil,
 		MsgType: MsgTypeVideo,
 	}
-	req.Voice = map[string]interface{}{
+	req.Mpvideo = map[string]interface{}{
 		"media_id":    mediaID,
 		"title":       title,
 		"description": description,
 	}
+	req.ClientMsgID = firstClientMsgID(clientMsgID)
 	req, sendURL := broadcast.chooseTagOrOpenID(user, req)
 	url := fmt.Sprintf("%s?access_token=%s", sendURL, ak)
 	data, err := util.PostJSON(url, req)
@@ -209,7 +224,7 @@ func (broadcast *Broadcast) SendVideo(user *User, mediaID string, title, descrip
 }
 
 //SendWxCard 发送卡券
-func (broadcast *Broadcast) SendWxCard(user *User, cardID string) (*Result, error) {
+func (broadcast *Broadcast) SendWxCard(user *User, cardID string, clientMsgID ...string) (*Result, error) {
 	ak, err := broadcast.GetAccessToken()
 	if err != nil {
 		return nil, err
@@ -221,6 +236,7 @@ func (broadcast *Broadcast) SendWxCard(user *User, cardID string) (*Result, erro
 	req.WxCard = map[string]interface{}{
 		"card_id": cardID,
 	}
+	req.ClientMsgID = firstClientMsgID(clientMsgID)
 	req, sendURL := broadcast.chooseTagOrOpenID(user, req)
 	url := fmt.Sprintf("%s?access_token=%s", sendURL, ak)
 	data, err := util.PostJSON(url, req)
@@ -250,7 +266,119 @@ func (broadcast *Broadcast) Delete(msgID int64, articleIDx int64) error {
 	return util.DecodeWithCommonError(data, "Delete")
 }
 
-//TODO 发送预览，群发消息状态，发送速度
+//PreviewUser 预览消息的接收者，OpenID 与 WxName 同时填写时以 WxName 优先
+type PreviewUser struct {
+	OpenID string
+	WxName string
+}
+
+//Preview 预览群发消息，content 对于文本为文本内容，对于卡券为card_id，其他类型为media_id
+func (broadcast *Broadcast) Preview(user *PreviewUser, msgType MsgType, content string) (*Result, error) {
+	if user == nil || (user.WxName == "" && user.OpenID == "") {
+		return nil, errors.New("preview user is empty")
+	}
+	ak, err := broadcast.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	req := map[string]interface{}{
+		"msgtype": msgType,
+	}
+	if user.WxName != "" {
+		req["towxname"] = user.WxName
+	} else {
+		req["touser"] = user.OpenID
+	}
+	switch msgType {
+	case MsgTypeText:
+		req[string(msgType)] = map[string]string{"content": content}
+	case MsgTypeWxCard:
+		req[string(msgType)] = map[string]string{"card_id": content}
+	default:
+		req[string(msgType)] = map[string]string{"media_id": content}
+	}
+	url := fmt.Sprintf("%s?access_token=%s", previewSendURL, ak)
+	data, err := util.PostJSON(url, req)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{}
+	err = util.DecodeWithError(data, res, "Preview")
+	return res, err
+}
+
+//MassStatus 群发消息发送状态
+type MassStatus struct {
+	util.CommonError
+	MsgID     int64  `json:"msg_id"`
+	MsgStatus string `json:"msg_status"` //SEND_SUCCESS 表示发送成功，SENDING 表示发送中，SEND_FAIL 表示发送失败，DELETE 表示已删除
+}
+
+//GetMassStatus 查询群发消息发送状态
+func (broadcast *Broadcast) GetMassStatus(msgID int64) (*MassStatus, error) {
+	ak, err := broadcast.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	req := map[string]interface{}{
+		"msg_id": msgID,
+	}
+	url := fmt.Sprintf("%s?access_token=%s", getSendURL, ak)
+	data, err := util.PostJSON(url, req)
+	if err != nil {
+		return nil, err
+	}
+	res := &MassStatus{}
+	err = util.DecodeWithError(data, res, "GetMassStatus")
+	return res, err
+}
+
+//Speed 群发速度
+type Speed struct {
+	util.CommonError
+	Speed     int `json:"speed"`     //群发速度的级别，0-4，0 为最快
+	RealSpeed int `json:"realspeed"` //群发速度的真实值，单位：万/分钟
+}
+
+//GetSpeed 获取群发速度
+func (broadcast *Broadcast) GetSpeed() (*Speed, error) {
+	ak, err := broadcast.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s?access_token=%s", getSpeedURL, ak)
+	data, err := util.PostJSON(url, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	res := &Speed{}
+	err = util.DecodeWithError(data, res, "GetSpeed")
+	return res, err
+}
+
+//SetSpeed 设置群发速度，speed 为群发速度的级别，0-4，0 为最快
+func (broadcast *Broadcast) SetSpeed(speed int) error {
+	ak, err := broadcast.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	req := map[string]interface{}{
+		"speed": speed,
+	}
+	url := fmt.Sprintf("%s?access_token=%s", setSpeedURL, ak)
+	data, err := util.PostJSON(url, req)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithCommonError(data, "SetSpeed")
+}
+
+func firstClientMsgID(clientMsgID []string) string {
+	if len(clientMsgID) > 0 {
+		return clientMsgID[0]
+	}
+	return ""
+}
 
 func (broadcast *Broadcast) chooseTagOrOpenID(user *User, req *sendRequest) (ret *sendRequest, url string) {
 	sendURL := ""
@@ -0,0 +1,328 @@
+package card
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	createURL      = "https://api.weixin.qq.com/card/create"
+	getURL         = "https://api.weixin.qq.com/card/get"
+	batchGetURL    = "https://api.weixin.qq.com/card/batchget"
+	updateURL      = "https://api.weixin.qq.com/card/update"
+	modifyStockURL = "https://api.weixin.qq.com/card/modifystock"
+	deleteURL      = "https://api.weixin.qq.com/card/delete"
+	userCardsURL   = "https://api.weixin.qq.com/card/user/getcardlist"
+)
+
+// Type 卡券类型
+type Type string
+
+const (
+	// TypeGroupon 团购券
+	TypeGroupon Type = "GROUPON"
+	// TypeCash 代金券
+	TypeCash Type = "CASH"
+	// TypeDiscount 折扣券
+	TypeDiscount Type = "DISCOUNT"
+	// TypeGift 兑换券
+	TypeGift Type = "GIFT"
+	// TypeGeneralCoupon 优惠券
+	TypeGeneralCoupon Type = "GENERAL_COUPON"
+	// TypeMemberCard 会员卡
+	TypeMemberCard Type = "MEMBER_CARD"
+)
+
+// Status 卡券状态
+type Status string
+
+const (
+	// StatusNotVerify 待审核
+	StatusNotVerify Status = "CARD_STATUS_NOT_VERIFY"
+	// StatusVerifyFail 审核失败
+	StatusVerifyFail Status = "CARD_STATUS_VERIFY_FAIL"
+	// StatusVerifyOK 审核通过
+	StatusVerifyOK Status = "CARD_STATUS_VERIFY_OK"
+	// StatusDelete 卡券被商户删除
+	StatusDelete Status = "CARD_STATUS_DELETE"
+	// StatusDispatch 在公众平台投放过的卡券
+	StatusDispatch Status = "CARD_STATUS_DISPATCH"
+)
+
+// Card 微信卡券
+type Card struct {
+	*context.Context
+}
+
+// NewCard init
+func NewCard(ctx *context.Context) *Card {
+	return &Card{
+		Context: ctx,
+	}
+}
+
+// Info 卡券信息，根据 CardType 填写对应类型的字段
+type Info struct {
+	CardType      Type           `json:"card_type"`
+	Groupon       *Groupon       `json:"groupon,omitempty"`
+	Cash          *Cash          `json:"cash,omitempty"`
+	Discount      *Discount      `json:"discount,omitempty"`
+	Gift          *Gift          `json:"gift,omitempty"`
+	GeneralCoupon *GeneralCoupon `json:"general_coupon,omitempty"`
+	MemberCard    *MemberCard    `json:"member_card,omitempty"`
+}
+
+// Groupon 团购券
+type Groupon struct {
+	BaseInfo     *BaseInfo     `json:"base_info"`
+	AdvancedInfo *AdvancedInfo `json:"advanced_info,omitempty"`
+	DealDetail   string        `json:"deal_detail"` // 团购券专用，团购详情
+}
+
+// Cash 代金券
+type Cash struct {
+	BaseInfo     *BaseInfo     `json:"base_info"`
+	AdvancedInfo *AdvancedInfo `json:"advanced_info,omitempty"`
+	LeastCost    int64         `json:"least_cost"`  // 起用金额，单位为分，0 表示无门槛
+	ReduceCost   int64         `json:"reduce_cost"` // 减免金额，单位为分
+}
+
+// Discount 折扣券
+type Discount struct {
+	BaseInfo     *BaseInfo     `json:"base_info"`
+	AdvancedInfo *AdvancedInfo `json:"advanced_info,omitempty"`
+	Discount     int           `json:"discount"` // 打折额度，填30就是七折
+}
+
+// Gift 兑换券
+type Gift struct {
+	BaseInfo     *BaseInfo     `json:"base_info"`
+	AdvancedInfo *AdvancedInfo `json:"advanced_info,omitempty"`
+	Gift         string        `json:"gift"` // 兑换内容的名称
+}
+
+// GeneralCoupon 优惠券
+type GeneralCoupon struct {
+	BaseInfo      *BaseInfo     `json:"base_info"`
+	AdvancedInfo  *AdvancedInfo `json:"advanced_info,omitempty"`
+	DefaultDetail string        `json:"default_detail"` // 优惠详情
+}
+
+// MemberCard 会员卡
+type MemberCard struct {
+	BaseInfo         *BaseInfo     `json:"base_info"`
+	AdvancedInfo     *AdvancedInfo `json:"advanced_info,omitempty"`
+	BackgroundPicURL string        `json:"background_pic_url,omitempty"`
+	Prerogative      string        `json:"prerogative"` // 会员卡特权说明
+	AutoActivate     bool          `json:"auto_activate,omitempty"`
+	WxActivate       bool          `json:"wx_activate,omitempty"` // 是否开通一键激活
+	SupplyBonus      bool          `json:"supply_bonus"`          // 是否支持积分
+	BonusURL         string        `json:"bonus_url,omitempty"`
+	SupplyBalance    bool          `json:"supply_balance"` // 是否支持储值
+	BalanceURL       string        `json:"balance_url,omitempty"`
+	ActivateURL      string        `json:"activate_url,omitempty"`
+	Discount         int           `json:"discount,omitempty"`
+
+	WxActivateAfterSubmit    bool         `json:"wx_activate_after_submit,omitempty"` // 是否开启一键激活后跳转到商户页面
+	WxActivateAfterSubmitURL string       `json:"wx_activate_after_submit_url,omitempty"`
+	CustomField1             *CustomField `json:"custom_field1,omitempty"`
+	CustomField2             *CustomField `json:"custom_field2,omitempty"`
+	CustomField3             *CustomField `json:"custom_field3,omitempty"`
+	BonusRule                *BonusRule   `json:"bonus_rule,omitempty"`
+}
+
+// CustomField 会员卡自定义信息类目
+type CustomField struct {
+	NameType string `json:"name_type,omitempty"` // FIELD_NAME_TYPE_LEVEL 等级，FIELD_NAME_TYPE_COUPON 优惠券 等
+	Name     string `json:"name,omitempty"`      // 自定义名称，与 NameType 二选一
+	URL      string `json:"url,omitempty"`
+}
+
+// BonusRule 积分规则
+type BonusRule struct {
+	CostMoneyUnit        int64 `json:"cost_money_unit,omitempty"`          // 消费金额，单位为分
+	IncreaseBonus        int64 `json:"increase_bonus,omitempty"`           // 对应增加的积分
+	MaxIncreaseBonus     int64 `json:"max_increase_bonus,omitempty"`       // 单次获取的积分上限
+	InitIncreaseBonus    int64 `json:"init_increase_bonus,omitempty"`      // 初始设置积分
+	CostBonusUnit        int64 `json:"cost_bonus_unit,omitempty"`          // 每使用的积分
+	ReduceMoney          int64 `json:"reduce_money,omitempty"`             // 抵扣的金额，单位为分
+	LeastMoneyToUseBonus int64 `json:"least_money_to_use_bonus,omitempty"` // 抵扣条件，满足的金额，单位为分
+	MaxReduceBonus       int64 `json:"max_reduce_bonus,omitempty"`         // 单笔最多使用的积分
+}
+
+// BaseInfo 卡券基础信息
+type BaseInfo struct {
+	ID                string   `json:"id,omitempty"`     // 卡券ID，仅在查询时返回
+	Status            Status   `json:"status,omitempty"` // 卡券状态，仅在查询时返回
+	LogoURL           string   `json:"logo_url"`
+	BrandName         string   `json:"brand_name"`
+	CodeType          string   `json:"code_type"` // CODE_TYPE_TEXT、CODE_TYPE_BARCODE、CODE_TYPE_QRCODE、CODE_TYPE_NONE
+	Title             string   `json:"title"`
+	Color             string   `json:"color"` // Color010 等
+	Notice            string   `json:"notice"`
+	ServicePhone      string   `json:"service_phone,omitempty"`
+	Description       string   `json:"description"`
+	DateInfo          DateInfo `json:"date_info"`
+	Sku               Sku      `json:"sku"`
+	UseLimit          int      `json:"use_limit,omitempty"`
+	GetLimit          int      `json:"get_limit,omitempty"`
+	UseCustomCode     bool     `json:"use_custom_code,omitempty"`
+	GetCustomCodeMode string   `json:"get_custom_code_mode,omitempty"`
+	BindOpenID        bool     `json:"bind_openid,omitempty"`
+	CanShare          bool     `json:"can_share,omitempty"`
+	CanGiveFriend     bool     `json:"can_give_friend,omitempty"`
+	LocationIDList    []int64  `json:"location_id_list,omitempty"`
+	UseAllLocations   bool     `json:"use_all_locations,omitempty"`
+	CenterTitle       string   `json:"center_title,omitempty"`
+	CenterSubTitle    string   `json:"center_sub_title,omitempty"`
+	CenterURL         string   `json:"center_url,omitempty"`
+	CustomURLName     string   `json:"custom_url_name,omitempty"`
+	CustomURL         string   `json:"custom_url,omitempty"`
+	CustomURLSubTitle string   `json:"custom_url_sub_title,omitempty"`
+	PromotionURLName  string   `json:"promotion_url_name,omitempty"`
+	PromotionURL      string   `json:"promotion_url,omitempty"`
+	Source            string   `json:"source,omitempty"`
+}
+
+// DateInfo 卡券有效期
+type DateInfo struct {
+	Type           string `json:"type"` // DATE_TYPE_FIX_TIME_RANGE 固定日期区间，DATE_TYPE_FIX_TERM 固定时长，DATE_TYPE_PERMANENT 永久有效
+	BeginTimestamp int64  `json:"begin_timestamp,omitempty"`
+	EndTimestamp   int64  `json:"end_timestamp,omitempty"`
+	FixedTerm      int    `json:"fixed_term,omitempty"`
+	FixedBeginTerm int    `json:"fixed_begin_term,omitempty"`
+}
+
+// Sku 卡券库存
+type Sku struct {
+	Quantity      int64 `json:"quantity"`
+	TotalQuantity int64 `json:"total_quantity,omitempty"`
+}
+
+// AdvancedInfo 卡券高级信息
+type AdvancedInfo struct {
+	UseCondition *struct {
+		AcceptCategory          string `json:"accept_category,omitempty"`
+		RejectCategory          string `json:"reject_category,omitempty"`
+		LeastCost               int64  `json:"least_cost,omitempty"`
+		CanUseWithOtherDiscount bool   `json:"can_use_with_other_discount"`
+	} `json:"use_condition,omitempty"`
+	Abstract *struct {
+		Abstract    string   `json:"abstract"`
+		IconURLList []string `json:"icon_url_list"`
+	} `json:"abstract,omitempty"`
+	TextImageList []struct {
+		ImageURL string `json:"image_url"`
+		Text     string `json:"text"`
+	} `json:"text_image_list,omitempty"`
+	BusinessService []string `json:"business_service,omitempty"`
+}
+
+// UserCard 用户已领取的卡券
+type UserCard struct {
+	CardID string `json:"card_id"`
+	Code   string `json:"code"`
+}
+
+// Create 创建卡券，返回card_id
+func (card *Card) Create(info *Info) (cardID string, err error) {
+	var res struct {
+		util.CommonError
+		CardID string `json:"card_id"`
+	}
+	err = card.post(createURL, map[string]*Info{"card": info}, &res, "CreateCard")
+	return res.CardID, err
+}
+
+// Get 查看卡券详情
+func (card *Card) Get(cardID string) (*Info, error) {
+	var res struct {
+		util.CommonError
+		Card *Info `json:"card"`
+	}
+	if err := card.post(getURL, map[string]string{"card_id": cardID}, &res, "GetCard"); err != nil {
+		return nil, err
+	}
+	return res.Card, nil
+}
+
+// BatchGet 批量查询卡券列表，count 最大为50，statusList 为空时不过滤状态
+func (card *Card) BatchGet(offset, count int, statusList ...Status) (cardIDs []string, total int, err error) {
+	req := map[string]interface{}{
+		"offset": offset,
+		"count":  count,
+	}
+	if len(statusList) > 0 {
+		req["status_list"] = statusList
+	}
+	var res struct {
+		util.CommonError
+		CardIDList []string `json:"card_id_list"`
+		TotalNum   int      `json:"total_num"`
+	}
+	err = card.post(batchGetURL, req, &res, "BatchGetCard")
+	return res.CardIDList, res.TotalNum, err
+}
+
+// Update 更改卡券信息，detail 为对应卡券类型的结构（如 *Cash），只需填写要修改的字段
+// 返回是否需要重新提交审核
+func (card *Card) Update(cardID string, cardType Type, detail interface{}) (sendCheck bool, err error) {
+	req := map[string]interface{}{
+		"card_id":                         cardID,
+		strings.ToLower(string(cardType)): detail,
+	}
+	var res struct {
+		util.CommonError
+		SendCheck bool `json:"send_check"`
+	}
+	err = card.post(updateURL, req, &res, "UpdateCard")
+	return res.SendCheck, err
+}
+
+// ModifyStock 修改库存，increase 与 reduce 分别为增加和减少的数量
+func (card *Card) ModifyStock(cardID string, increase, reduce int64) error {
+	req := map[string]interface{}{
+		"card_id":              cardID,
+		"increase_stock_value": increase,
+		"reduce_stock_value":   reduce,
+	}
+	var res struct{ util.CommonError }
+	return card.post(modifyStockURL, req, &res, "ModifyStock")
+}
+
+// Delete 删除卡券，删除后用户已领取的卡券也将失效
+func (card *Card) Delete(cardID string) error {
+	var res struct{ util.CommonError }
+	return card.post(deleteURL, map[string]string{"card_id": cardID}, &res, "DeleteCard")
+}
+
+// GetUserCardList 获取用户已领取的卡券，cardID 为空时返回全部
+func (card *Card) GetUserCardList(openID, cardID string) ([]UserCard, error) {
+	req := map[string]string{
+		"openid":  openID,
+		"card_id": cardID,
+	}
+	var res struct {
+		util.CommonError
+		CardList []UserCard `json:"card_list"`
+	}
+	if err := card.post(userCardsURL, req, &res, "GetUserCardList"); err != nil {
+		return nil, err
+	}
+	return res.CardList, nil
+}
+
+func (card *Card) post(apiURL string, req, res interface{}, apiName string) error {
+	accessToken, err := card.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	response, err := util.PostJSON(fmt.Sprintf("%s?access_token=%s", apiURL, accessToken), req)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithError(response, res, apiName)
+}
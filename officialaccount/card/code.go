@@ -0,0 +1,153 @@
+package card
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	depositCodeURL     = "https://api.weixin.qq.com/card/code/deposit"
+	getDepositCountURL = "https://api.weixin.qq.com/card/code/getdepositcount"
+	checkCodeURL       = "https://api.weixin.qq.com/card/code/checkcode"
+	getCodeURL         = "https://api.weixin.qq.com/card/code/get"
+	consumeCodeURL     = "https://api.weixin.qq.com/card/code/consume"
+	decryptCodeURL     = "https://api.weixin.qq.com/card/code/decrypt"
+	unavailableCodeURL = "https://api.weixin.qq.com/card/code/unavailable"
+	depositCodeLimit   = 100
+	checkCodeLimit     = 100
+)
+
+// CodeInfo 查询code的结果
+type CodeInfo struct {
+	OpenID string `json:"openid"`
+	Card   struct {
+		CardID    string `json:"card_id"`
+		BeginTime int64  `json:"begin_time"`
+		EndTime   int64  `json:"end_time"`
+	} `json:"card"`
+	CanConsume     bool   `json:"can_consume"`
+	UserCardStatus string `json:"user_card_status"` // NORMAL 正常，CONSUMED 已核销，EXPIRE 已过期，GIFTING 转赠中，GIFT_TIMEOUT 转赠超时，DELETE 已删除，UNAVAILABLE 已失效
+}
+
+// DepositCodeResult 导入自定义code的结果
+type DepositCodeResult struct {
+	SuccCode      []string `json:"succ_code"`      // 导入成功的code
+	DuplicateCode []string `json:"duplicate_code"` // 重复导入的code
+	FailCode      []string `json:"fail_code"`      // 导入失败的code
+}
+
+// DepositCode 导入自定义code，超过100个时自动分批；某一批失败时返回已完成批次的结果与错误
+func (card *Card) DepositCode(cardID string, codes []string) (*DepositCodeResult, error) {
+	result := &DepositCodeResult{}
+	for start := 0; start < len(codes); start += depositCodeLimit {
+		end := start + depositCodeLimit
+		if end > len(codes) {
+			end = len(codes)
+		}
+		req := map[string]interface{}{
+			"card_id": cardID,
+			"code":    codes[start:end],
+		}
+		var res struct {
+			util.CommonError
+			DepositCodeResult
+		}
+		if err := card.post(depositCodeURL, req, &res, "DepositCode"); err != nil {
+			return result, fmt.Errorf("%v, code offset=%d", err, start)
+		}
+		result.SuccCode = append(result.SuccCode, res.SuccCode...)
+		result.DuplicateCode = append(result.DuplicateCode, res.DuplicateCode...)
+		result.FailCode = append(result.FailCode, res.FailCode...)
+	}
+	return result, nil
+}
+
+// GetDepositCount 查询导入code的数目
+func (card *Card) GetDepositCount(cardID string) (int64, error) {
+	var res struct {
+		util.CommonError
+		Count int64 `json:"count"`
+	}
+	err := card.post(getDepositCountURL, map[string]string{"card_id": cardID}, &res, "GetDepositCount")
+	return res.Count, err
+}
+
+// CheckCode 核查code是否已导入，超过100个时自动分批
+func (card *Card) CheckCode(cardID string, codes []string) (exist, notExist []string, err error) {
+	for start := 0; start < len(codes); start += checkCodeLimit {
+		end := start + checkCodeLimit
+		if end > len(codes) {
+			end = len(codes)
+		}
+		req := map[string]interface{}{
+			"card_id": cardID,
+			"code":    codes[start:end],
+		}
+		var res struct {
+			util.CommonError
+			ExistCode    []string `json:"exist_code"`
+			NotExistCode []string `json:"not_exist_code"`
+		}
+		if err = card.post(checkCodeURL, req, &res, "CheckCode"); err != nil {
+			return nil, nil, fmt.Errorf("%v, code offset=%d", err, start)
+		}
+		exist = append(exist, res.ExistCode...)
+		notExist = append(notExist, res.NotExistCode...)
+	}
+	return exist, notExist, nil
+}
+
+// GetCode 查询code，checkConsume 为true时同时校验code是否可以核销
+func (card *Card) GetCode(cardID, code string, checkConsume bool) (*CodeInfo, error) {
+	req := map[string]interface{}{
+		"card_id":       cardID,
+		"code":          code,
+		"check_consume": checkConsume,
+	}
+	var res struct {
+		util.CommonError
+		CodeInfo
+	}
+	if err := card.post(getCodeURL, req, &res, "GetCode"); err != nil {
+		return nil, err
+	}
+	return &res.CodeInfo, nil
+}
+
+// ConsumeCode 核销code，cardID 仅在自定义code卡券时必填
+func (card *Card) ConsumeCode(code, cardID string) (openID string, err error) {
+	req := map[string]string{
+		"code": code,
+	}
+	if cardID != "" {
+		req["card_id"] = cardID
+	}
+	var res struct {
+		util.CommonError
+		OpenID string `json:"openid"`
+	}
+	err = card.post(consumeCodeURL, req, &res, "ConsumeCode")
+	return res.OpenID, err
+}
+
+// DecryptCode 解码跳转链接中的加密code
+func (card *Card) DecryptCode(encryptCode string) (code string, err error) {
+	var res struct {
+		util.CommonError
+		Code string `json:"code"`
+	}
+	err = card.post(decryptCodeURL, map[string]string{"encrypt_code": encryptCode}, &res, "DecryptCode")
+	return res.Code, err
+}
+
+// UnavailableCode 设置卡券失效，reason 为失效理由
+func (card *Card) UnavailableCode(cardID, code, reason string) error {
+	req := map[string]string{
+		"card_id": cardID,
+		"code":    code,
+		"reason":  reason,
+	}
+	var res struct{ util.CommonError }
+	return card.post(unavailableCodeURL, req, &res, "UnavailableCode")
+}
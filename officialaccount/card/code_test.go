@@ -0,0 +1,125 @@
+package card
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func newTestCard() *Card {
+	return NewCard(&context.Context{AccessTokenHandle: mockAccessToken{}})
+}
+
+func makeCodes(n int) []string {
+	codes := make([]string, n)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("code%03d", i)
+	}
+	return codes
+}
+
+// mockCodeBatches 按每批最多100个code注册请求，校验每批的code并按 respond 返回结果
+func mockCodeBatches(apiURL string, codes []string, respond func(batch []string) map[string]interface{}) {
+	for start := 0; start < len(codes); start += 100 {
+		end := start + 100
+		if end > len(codes) {
+			end = len(codes)
+		}
+		batch := codes[start:end]
+		gock.New(apiURL).MatchParam("access_token", "mock-ak").
+			AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+				var body struct {
+					CardID string   `json:"card_id"`
+					Code   []string `json:"code"`
+				}
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					return false, err
+				}
+				return body.CardID == "card1" && assert.ObjectsAreEqual(batch, body.Code), nil
+			}).
+			Reply(200).JSON(respond(batch))
+	}
+}
+
+func TestDepositCodeBatches(t *testing.T) {
+	defer gock.Off()
+	card := newTestCard()
+
+	//没有code时不发起请求
+	res, err := card.DepositCode("card1", nil)
+	assert.Nil(t, err)
+	assert.Empty(t, res.SuccCode)
+
+	for _, n := range []int{100, 101} {
+		codes := makeCodes(n)
+		mockCodeBatches(depositCodeURL, codes, func(codes []string) map[string]interface{} {
+			return map[string]interface{}{
+				"succ_code":      codes[1:],
+				"duplicate_code": codes[:1],
+				"fail_code":      []string{},
+			}
+		})
+		res, err = card.DepositCode("card1", codes)
+		assert.Nil(t, err)
+		assert.True(t, gock.IsDone())
+		if n == 100 {
+			assert.Equal(t, []string{"code000"}, res.DuplicateCode)
+		} else {
+			assert.Equal(t, []string{"code000", "code100"}, res.DuplicateCode)
+		}
+		assert.Len(t, res.SuccCode, 99)
+		assert.Empty(t, res.FailCode)
+	}
+}
+
+func TestDepositCodeBatchError(t *testing.T) {
+	defer gock.Off()
+	codes := makeCodes(101)
+	mockCodeBatches(depositCodeURL, codes[:100], func(codes []string) map[string]interface{} {
+		return map[string]interface{}{"succ_code": codes, "fail_code": []string{}}
+	})
+	gock.New(depositCodeURL).Reply(200).JSON(map[string]interface{}{"errcode": 40056, "errmsg": "invalid serial code"})
+
+	res, err := newTestCard().DepositCode("card1", codes)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "code offset=100")
+	assert.Len(t, res.SuccCode, 100)
+	assert.True(t, gock.IsDone())
+}
+
+func TestCheckCodeBatches(t *testing.T) {
+	defer gock.Off()
+	card := newTestCard()
+
+	exist, notExist, err := card.CheckCode("card1", nil)
+	assert.Nil(t, err)
+	assert.Empty(t, exist)
+	assert.Empty(t, notExist)
+
+	for _, n := range []int{100, 101} {
+		codes := makeCodes(n)
+		mockCodeBatches(checkCodeURL, codes, func(codes []string) map[string]interface{} {
+			return map[string]interface{}{"exist_code": codes[:1], "not_exist_code": codes[1:]}
+		})
+		exist, notExist, err = card.CheckCode("card1", codes)
+		assert.Nil(t, err)
+		assert.True(t, gock.IsDone())
+		if n == 100 {
+			assert.Equal(t, []string{"code000"}, exist)
+		} else {
+			assert.Equal(t, []string{"code000", "code100"}, exist)
+		}
+		assert.Len(t, notExist, 99)
+	}
+}
@@ -0,0 +1,157 @@
+package card
+
+import (
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+)
+
+// Event 卡券事件的公共字段
+type Event struct {
+	OpenID       string
+	CardID       string
+	UserCardCode string
+	CreateTime   int64
+}
+
+func newEvent(msg message.MixMessage) Event {
+	return Event{
+		OpenID:       string(msg.FromUserName),
+		CardID:       msg.CardID,
+		UserCardCode: msg.UserCardCode,
+		CreateTime:   msg.CreateTime,
+	}
+}
+
+// CheckEvent 卡券审核事件
+type CheckEvent struct {
+	CardID       string
+	Passed       bool
+	RefuseReason string
+}
+
+// GetCardEvent 用户领取卡券事件
+type GetCardEvent struct {
+	Event
+	IsGiveByFriend      bool
+	FriendUserName      string
+	OldUserCardCode     string
+	OuterStr            string
+	IsRestoreMemberCard bool
+	UnionID             string
+}
+
+// GiftingCardEvent 用户转赠卡券事件
+type GiftingCardEvent struct {
+	Event
+	FriendUserName string
+	IsReturnBack   bool
+	IsChatRoom     bool
+}
+
+// ConsumeCardEvent 卡券核销事件
+type ConsumeCardEvent struct {
+	Event
+	ConsumeSource string // FROM_API、FROM_MOBILE_HELPER 等核销来源
+	LocationName  string
+	StaffOpenID   string
+	VerifyCode    string
+	RemarkAmount  string
+	OuterStr      string
+}
+
+// ViewCardEvent 用户进入会员卡事件
+type ViewCardEvent struct {
+	Event
+	OuterStr string
+}
+
+// UpdateMemberCardEvent 会员卡积分余额变更事件
+type UpdateMemberCardEvent struct {
+	Event
+	ModifyBonus   int64 // 变动的积分值
+	ModifyBalance int64 // 变动的余额值
+}
+
+// SkuRemindEvent 库存报警事件
+type SkuRemindEvent struct {
+	CardID string
+	Detail string
+}
+
+// EventHandler 卡券事件处理，只需设置关心的事件
+type EventHandler struct {
+	OnCheck        func(e *CheckEvent) *message.Reply
+	OnGetCard      func(e *GetCardEvent) *message.Reply
+	OnGiftingCard  func(e *GiftingCardEvent) *message.Reply
+	OnDelCard      func(e *Event) *message.Reply
+	OnConsumeCard  func(e *ConsumeCardEvent) *message.Reply
+	OnViewCard     func(e *ViewCardEvent) *message.Reply
+	OnEnterSession func(e *Event) *message.Reply
+	OnSkuRemind    func(e *SkuRemindEvent) *message.Reply
+
+	OnSubmitMemberCardUserInfo func(e *Event) *message.Reply
+	OnUpdateMemberCard         func(e *UpdateMemberCardEvent) *message.Reply
+}
+
+// Handle 处理卡券事件，没有对应的处理方法时返回false
+func (h *EventHandler) Handle(msg message.MixMessage) (*message.Reply, bool) {
+	if msg.MsgType != message.MsgTypeEvent {
+		return nil, false
+	}
+	e := newEvent(msg)
+	switch msg.Event {
+	case message.EventCardPassCheck, message.EventCardNotPassCheck:
+		if h.OnCheck != nil {
+			return h.OnCheck(&CheckEvent{CardID: msg.CardID, Passed: msg.Event == message.EventCardPassCheck, RefuseReason: msg.RefuseReason}), true
+		}
+	case message.EventUserGetCard:
+		if h.OnGetCard != nil {
+			return h.OnGetCard(&GetCardEvent{e, msg.IsGiveByFriend == 1, msg.FriendUserName, msg.OldUserCardCode, msg.OuterStr, msg.IsRestoreMemberCard == 1, msg.UnionID}), true
+		}
+	case message.EventUserGiftingCard:
+		if h.OnGiftingCard != nil {
+			return h.OnGiftingCard(&GiftingCardEvent{e, msg.FriendUserName, msg.IsReturnBack == 1, msg.IsChatRoom == 1}), true
+		}
+	case message.EventUserDelCard:
+		if h.OnDelCard != nil {
+			return h.OnDelCard(&e), true
+		}
+	case message.EventUserConsumeCard:
+		if h.OnConsumeCard != nil {
+			return h.OnConsumeCard(&ConsumeCardEvent{e, msg.ConsumeSource, msg.LocationName, msg.StaffOpenID, msg.VerifyCode, msg.RemarkAmount, msg.OuterStr}), true
+		}
+	case message.EventUserViewCard:
+		if h.OnViewCard != nil {
+			return h.OnViewCard(&ViewCardEvent{e, msg.OuterStr}), true
+		}
+	case message.EventUserEnterSessionFromCard:
+		if h.OnEnterSession != nil {
+			return h.OnEnterSession(&e), true
+		}
+	case message.EventCardSkuRemind:
+		if h.OnSkuRemind != nil {
+			return h.OnSkuRemind(&SkuRemindEvent{CardID: msg.CardID, Detail: msg.Detail}), true
+		}
+	case message.EventSubmitMemberCardUserInfo:
+		if h.OnSubmitMemberCardUserInfo != nil {
+			return h.OnSubmitMemberCardUserInfo(&e), true
+		}
+	case message.EventUpdateMemberCard:
+		if h.OnUpdateMemberCard != nil {
+			return h.OnUpdateMemberCard(&UpdateMemberCardEvent{e, msg.ModifyBonus, msg.ModifyBalance}), true
+		}
+	}
+	return nil, false
+}
+
+// Handler 包装消息处理方法，卡券事件由 EventHandler 处理，其余消息交给next，可直接传给 server.SetMessageHandler
+func (h *EventHandler) Handler(next func(msg message.MixMessage) *message.Reply) func(msg message.MixMessage) *message.Reply {
+	return func(msg message.MixMessage) *message.Reply {
+		if reply, ok := h.Handle(msg); ok {
+			return reply
+		}
+		if next == nil {
+			return nil
+		}
+		return next(msg)
+	}
+}
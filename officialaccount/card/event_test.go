@@ -0,0 +1,76 @@
+package card
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventHandler(t *testing.T) {
+	raw := `<xml>
+<ToUserName><![CDATA[toUser]]></ToUserName>
+<FromUserName><![CDATA[FromUser]]></FromUserName>
+<CreateTime>123456789</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[user_consume_card]]></Event>
+<CardId><![CDATA[4ZYsPQNhTNqZ5Q8vMeEyGTahpu9I]]></CardId>
+<UserCardCode><![CDATA[12312312]]></UserCardCode>
+<ConsumeSource><![CDATA[FROM_API]]></ConsumeSource>
+<LocationName><![CDATA[]]></LocationName>
+<StaffOpenId><![CDATA[oFS7Fjl0WsZ9AMZqrI80nbIq8xrA]]></StaffOpenId>
+<VerifyCode><![CDATA[]]></VerifyCode>
+<RemarkAmount><![CDATA[]]></RemarkAmount>
+<OuterStr><![CDATA[xxxxx]]></OuterStr>
+</xml>`
+	var msg message.MixMessage
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &msg))
+
+	var got *ConsumeCardEvent
+	h := &EventHandler{
+		OnConsumeCard: func(e *ConsumeCardEvent) *message.Reply {
+			got = e
+			return nil
+		},
+	}
+	_, ok := h.Handle(msg)
+	assert.True(t, ok)
+	assert.Equal(t, "FromUser", got.OpenID)
+	assert.Equal(t, "4ZYsPQNhTNqZ5Q8vMeEyGTahpu9I", got.CardID)
+	assert.Equal(t, "12312312", got.UserCardCode)
+	assert.Equal(t, "FROM_API", got.ConsumeSource)
+	assert.Equal(t, "oFS7Fjl0WsZ9AMZqrI80nbIq8xrA", got.StaffOpenID)
+
+	msg.Event = message.EventUserDelCard
+	_, ok = h.Handle(msg)
+	assert.False(t, ok)
+}
+
+func TestUpdateMemberCardEvent(t *testing.T) {
+	raw := `<xml>
+<ToUserName><![CDATA[gh_9e1765b5568e]]></ToUserName>
+<FromUserName><![CDATA[ojZ8YtyVyr30HheH3CM73y7h4jJE]]></FromUserName>
+<CreateTime>1445507140</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[update_member_card]]></Event>
+<CardId><![CDATA[pjZ8Ytx-nwvpCRyQneH3Ncmh6N94]]></CardId>
+<UserCardCode><![CDATA[485027611252]]></UserCardCode>
+<ModifyBonus>3</ModifyBonus>
+<ModifyBalance>-1</ModifyBalance>
+</xml>`
+	var msg message.MixMessage
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &msg))
+
+	var got *UpdateMemberCardEvent
+	reply := (&EventHandler{
+		OnUpdateMemberCard: func(e *UpdateMemberCardEvent) *message.Reply {
+			got = e
+			return nil
+		},
+	}).Handler(nil)(msg)
+	assert.Nil(t, reply)
+	assert.Equal(t, "485027611252", got.UserCardCode)
+	assert.Equal(t, int64(3), got.ModifyBonus)
+	assert.Equal(t, int64(-1), got.ModifyBalance)
+}
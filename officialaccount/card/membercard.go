@@ -0,0 +1,201 @@
+package card
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	setActivateUserFormURL  = "https://api.weixin.qq.com/card/membercard/activateuserform/set"
+	activateMemberCardURL   = "https://api.weixin.qq.com/card/membercard/activate"
+	getActivateTempInfoURL  = "https://api.weixin.qq.com/card/membercard/activatetempinfo/get"
+	getMemberCardUserURL    = "https://api.weixin.qq.com/card/membercard/userinfo/get"
+	updateMemberCardUserURL = "https://api.weixin.qq.com/card/membercard/updateuser"
+)
+
+// ActivateUserForm 一键激活的开卡字段
+type ActivateUserForm struct {
+	CardID           string    `json:"card_id"`
+	ServiceStatement *FormLink `json:"service_statement,omitempty"` // 会员卡激活时的服务声明
+	BindOldCard      *FormLink `json:"bind_old_card,omitempty"`     // 绑定老会员卡的链接
+	RequiredForm     *FormInfo `json:"required_form,omitempty"`
+	OptionalForm     *FormInfo `json:"optional_form,omitempty"`
+}
+
+// FormLink 开卡页面中的链接
+type FormLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// FormInfo 开卡字段
+type FormInfo struct {
+	CanModify         bool        `json:"can_modify"`                     // 当前结构中的字段是否允许用户激活后再次修改
+	CommonFieldIDList []string    `json:"common_field_id_list,omitempty"` // 微信格式化的选项，如 USER_FORM_INFO_FLAG_MOBILE
+	CustomFieldList   []string    `json:"custom_field_list,omitempty"`    // 自定义的选项
+	RichFieldList     []RichField `json:"rich_field_list,omitempty"`      // 自定义富文本类型
+}
+
+// RichField 自定义富文本类型的开卡字段
+type RichField struct {
+	Type   string   `json:"type"` // FORM_FIELD_RADIO 单选，FORM_FIELD_SELECT 选择项，FORM_FIELD_CHECK_BOX 多选
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ActivateRequest 激活会员卡
+type ActivateRequest struct {
+	MembershipNumber      string `json:"membership_number"`
+	Code                  string `json:"code"`
+	CardID                string `json:"card_id,omitempty"`
+	BackgroundPicURL      string `json:"background_pic_url,omitempty"`
+	ActivateBeginTime     int64  `json:"activate_begin_time,omitempty"`
+	ActivateEndTime       int64  `json:"activate_end_time,omitempty"`
+	InitBonus             int64  `json:"init_bonus,omitempty"`
+	InitBonusRecord       string `json:"init_bonus_record,omitempty"`
+	InitBalance           int64  `json:"init_balance,omitempty"`
+	InitCustomFieldValue1 string `json:"init_custom_field_value1,omitempty"`
+	InitCustomFieldValue2 string `json:"init_custom_field_value2,omitempty"`
+	InitCustomFieldValue3 string `json:"init_custom_field_value3,omitempty"`
+}
+
+// UserFormInfo 用户填写的开卡信息
+type UserFormInfo struct {
+	CommonFieldList []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"common_field_list"`
+	CustomFieldList []struct {
+		Name      string   `json:"name"`
+		Value     string   `json:"value"`
+		ValueList []string `json:"value_list"`
+	} `json:"custom_field_list"`
+}
+
+// MemberCardUser 会员信息
+type MemberCardUser struct {
+	OpenID           string       `json:"openid"`
+	Nickname         string       `json:"nickname"`
+	MembershipNumber string       `json:"membership_number"`
+	Bonus            int64        `json:"bonus"`
+	Balance          int64        `json:"balance"`
+	Sex              string       `json:"sex"`
+	UserInfo         UserFormInfo `json:"user_info"`
+	UserCardStatus   string       `json:"user_card_status"`
+	HasActive        bool         `json:"has_active"`
+}
+
+// UpdateUserRequest 更新会员信息
+type UpdateUserRequest struct {
+	Code              string          `json:"code"`
+	CardID            string          `json:"card_id"`
+	BackgroundPicURL  string          `json:"background_pic_url,omitempty"`
+	Bonus             *int64          `json:"bonus,omitempty"`     // 需要设置的积分全量值，与 AddBonus 二选一
+	AddBonus          int64           `json:"add_bonus,omitempty"` // 本次积分变动值，负数代表减少
+	RecordBonus       string          `json:"record_bonus,omitempty"`
+	Balance           *int64          `json:"balance,omitempty"`     // 需要设置的余额全量值，与 AddBalance 二选一
+	AddBalance        int64           `json:"add_balance,omitempty"` // 本次余额变动值，负数代表减少
+	RecordBalance     string          `json:"record_balance,omitempty"`
+	CustomFieldValue1 string          `json:"custom_field_value1,omitempty"`
+	CustomFieldValue2 string          `json:"custom_field_value2,omitempty"`
+	CustomFieldValue3 string          `json:"custom_field_value3,omitempty"`
+	NotifyOptional    *NotifyOptional `json:"notify_optional,omitempty"`
+}
+
+// NotifyOptional 更新会员信息时是否向用户推送变动消息
+type NotifyOptional struct {
+	IsNotifyBonus        bool `json:"is_notify_bonus"`
+	IsNotifyBalance      bool `json:"is_notify_balance"`
+	IsNotifyCustomField1 bool `json:"is_notify_custom_field1"`
+	IsNotifyCustomField2 bool `json:"is_notify_custom_field2"`
+	IsNotifyCustomField3 bool `json:"is_notify_custom_field3"`
+}
+
+// UpdateUserResult 更新会员信息的结果
+type UpdateUserResult struct {
+	ResultBonus   int64  `json:"result_bonus"`
+	ResultBalance int64  `json:"result_balance"`
+	OpenID        string `json:"openid"`
+}
+
+// ActivateRedirect 一键激活后跳转到商户页面时携带的参数
+type ActivateRedirect struct {
+	CardID         string
+	EncryptCode    string // 通过 DecryptCode 解码为code
+	OpenID         string
+	ActivateTicket string // 通过 GetActivateTempInfo 获取用户填写的信息
+	OuterStr       string
+}
+
+// ParseActivateRedirect 解析一键激活后跳转到商户页面的参数
+func ParseActivateRedirect(req *http.Request) *ActivateRedirect {
+	query := req.URL.Query()
+	return &ActivateRedirect{
+		CardID:         query.Get("card_id"),
+		EncryptCode:    query.Get("encrypt_code"),
+		OpenID:         query.Get("openid"),
+		ActivateTicket: query.Get("activate_ticket"),
+		OuterStr:       query.Get("outer_str"),
+	}
+}
+
+// SetActivateUserForm 设置一键激活的开卡字段
+func (card *Card) SetActivateUserForm(form *ActivateUserForm) error {
+	var res struct{ util.CommonError }
+	return card.post(setActivateUserFormURL, form, &res, "SetActivateUserForm")
+}
+
+// ActivateMemberCard 接口激活会员卡
+func (card *Card) ActivateMemberCard(req *ActivateRequest) error {
+	var res struct{ util.CommonError }
+	return card.post(activateMemberCardURL, req, &res, "ActivateMemberCard")
+}
+
+// GetActivateTempInfo 通过 activate_ticket 获取用户在一键激活时填写的信息
+func (card *Card) GetActivateTempInfo(activateTicket string) (*UserFormInfo, error) {
+	var res struct {
+		util.CommonError
+		Info UserFormInfo `json:"info"`
+	}
+	if err := card.post(getActivateTempInfoURL, map[string]string{"activate_ticket": activateTicket}, &res, "GetActivateTempInfo"); err != nil {
+		return nil, err
+	}
+	return &res.Info, nil
+}
+
+// GetMemberCardUser 拉取会员信息
+func (card *Card) GetMemberCardUser(cardID, code string) (*MemberCardUser, error) {
+	req := map[string]string{
+		"card_id": cardID,
+		"code":    code,
+	}
+	var res struct {
+		util.CommonError
+		MemberCardUser
+	}
+	if err := card.post(getMemberCardUserURL, req, &res, "GetMemberCardUser"); err != nil {
+		return nil, err
+	}
+	return &res.MemberCardUser, nil
+}
+
+// UpdateMemberCardUser 更新会员积分、余额等信息，并可选择是否向用户推送变动消息
+// 全量值与变动值只能设置其一，同时设置 Bonus 与 AddBonus 或 Balance 与 AddBalance 时返回错误
+func (card *Card) UpdateMemberCardUser(req *UpdateUserRequest) (*UpdateUserResult, error) {
+	if req.Bonus != nil && req.AddBonus != 0 {
+		return nil, errors.New("UpdateMemberCardUser bonus and add_bonus can not be set at the same time")
+	}
+	if req.Balance != nil && req.AddBalance != 0 {
+		return nil, errors.New("UpdateMemberCardUser balance and add_balance can not be set at the same time")
+	}
+	var res struct {
+		util.CommonError
+		UpdateUserResult
+	}
+	if err := card.post(updateMemberCardUserURL, req, &res, "UpdateMemberCardUser"); err != nil {
+		return nil, err
+	}
+	return &res.UpdateUserResult, nil
+}
@@ -0,0 +1,88 @@
+package card
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestSetActivateUserForm(t *testing.T) {
+	defer gock.Off()
+	gock.New(setActivateUserFormURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"card_id":"card1","service_statement":{"name":"会员守则","url":"https://example.com/rule"},"required_form":{"can_modify":false,"common_field_id_list":["USER_FORM_INFO_FLAG_MOBILE"],"rich_field_list":[{"type":"FORM_FIELD_RADIO","name":"兴趣","values":["a","b"]}]}}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	err := newTestCard().SetActivateUserForm(&ActivateUserForm{
+		CardID:           "card1",
+		ServiceStatement: &FormLink{Name: "会员守则", URL: "https://example.com/rule"},
+		RequiredForm: &FormInfo{
+			CommonFieldIDList: []string{"USER_FORM_INFO_FLAG_MOBILE"},
+			RichFieldList:     []RichField{{Type: "FORM_FIELD_RADIO", Name: "兴趣", Values: []string{"a", "b"}}},
+		},
+	})
+	assert.Nil(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestActivateMemberCard(t *testing.T) {
+	defer gock.Off()
+	gock.New(activateMemberCardURL).
+		AddMatcher(matchBody(t, `{"membership_number":"M001","code":"code1","card_id":"card1","init_bonus":100,"init_bonus_record":"开卡赠送"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	err := newTestCard().ActivateMemberCard(&ActivateRequest{
+		MembershipNumber: "M001",
+		Code:             "code1",
+		CardID:           "card1",
+		InitBonus:        100,
+		InitBonusRecord:  "开卡赠送",
+	})
+	assert.Nil(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestGetActivateTempInfo(t *testing.T) {
+	defer gock.Off()
+	gock.New(getActivateTempInfoURL).
+		AddMatcher(matchBody(t, `{"activate_ticket":"ticket1"}`)).
+		Reply(200).JSON(map[string]interface{}{
+		"info": map[string]interface{}{
+			"common_field_list": []map[string]string{{"name": "USER_FORM_INFO_FLAG_MOBILE", "value": "13800000000"}},
+		},
+	})
+
+	info, err := newTestCard().GetActivateTempInfo("ticket1")
+	assert.Nil(t, err)
+	assert.Equal(t, "13800000000", info.CommonFieldList[0].Value)
+	assert.True(t, gock.IsDone())
+}
+
+func TestUpdateMemberCardUser(t *testing.T) {
+	defer gock.Off()
+	//全量设置为0时也需要传递
+	gock.New(updateMemberCardUserURL).
+		AddMatcher(matchBody(t, `{"code":"code1","card_id":"card1","bonus":0,"add_balance":-50,"record_balance":"消费","notify_optional":{"is_notify_bonus":true,"is_notify_balance":true,"is_notify_custom_field1":false,"is_notify_custom_field2":false,"is_notify_custom_field3":false}}`)).
+		Reply(200).JSON(map[string]interface{}{"result_bonus": 0, "result_balance": 150, "openid": "openid1"})
+
+	card := newTestCard()
+	bonus := int64(0)
+	res, err := card.UpdateMemberCardUser(&UpdateUserRequest{
+		Code:           "code1",
+		CardID:         "card1",
+		Bonus:          &bonus,
+		AddBalance:     -50,
+		RecordBalance:  "消费",
+		NotifyOptional: &NotifyOptional{IsNotifyBonus: true, IsNotifyBalance: true},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(150), res.ResultBalance)
+	assert.True(t, gock.IsDone())
+
+	//全量值与变动值同时设置时不发起请求
+	_, err = card.UpdateMemberCardUser(&UpdateUserRequest{Code: "code1", CardID: "card1", Bonus: &bonus, AddBonus: 10})
+	assert.NotNil(t, err)
+	balance := int64(100)
+	_, err = card.UpdateMemberCardUser(&UpdateUserRequest{Code: "code1", CardID: "card1", Balance: &balance, AddBalance: 10})
+	assert.NotNil(t, err)
+}
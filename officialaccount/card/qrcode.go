@@ -0,0 +1,81 @@
+package card
+
+import (
+	"errors"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	createQRCodeURL      = "https://api.weixin.qq.com/card/qrcode/create"
+	createLandingPageURL = "https://api.weixin.qq.com/card/landingpage/create"
+)
+
+// QRCard 二维码中投放的卡券
+type QRCard struct {
+	CardID       string `json:"card_id"`
+	Code         string `json:"code,omitempty"`   // 自定义code卡券必填
+	OpenID       string `json:"openid,omitempty"` // 指定领取者的openid
+	IsUniqueCode bool   `json:"is_unique_code,omitempty"`
+	OuterStr     string `json:"outer_str,omitempty"` // 领取场景值，会在领取事件中返回
+}
+
+// QRCodeResult 卡券二维码
+type QRCodeResult struct {
+	util.CommonError
+	Ticket        string `json:"ticket"`
+	ExpireSeconds int64  `json:"expire_seconds"`
+	URL           string `json:"url"`
+	ShowQRCodeURL string `json:"show_qrcode_url"`
+}
+
+// LandingPage 货架
+type LandingPage struct {
+	Banner    string            `json:"banner"`
+	PageTitle string            `json:"page_title"`
+	CanShare  bool              `json:"can_share"`
+	Scene     string            `json:"scene"` // 投放场景，如 SCENE_NEAR_BY、SCENE_MENU、SCENE_QRCODE 等
+	CardList  []LandingPageCard `json:"card_list"`
+}
+
+// LandingPageCard 货架中的卡券
+type LandingPageCard struct {
+	CardID   string `json:"card_id"`
+	ThumbURL string `json:"thumb_url"`
+}
+
+// CreateQRCode 创建领取卡券的二维码，expireSeconds 为0时为永久有效，多于1张卡券时生成一次领取多张的二维码
+func (card *Card) CreateQRCode(expireSeconds int64, cards ...*QRCard) (*QRCodeResult, error) {
+	if len(cards) == 0 {
+		return nil, errors.New("CreateCardQRCode cards is empty")
+	}
+	req := map[string]interface{}{}
+	if expireSeconds > 0 {
+		req["expire_seconds"] = expireSeconds
+	}
+	if len(cards) == 1 {
+		req["action_name"] = "QR_CARD"
+		req["action_info"] = map[string]interface{}{"card": cards[0]}
+	} else {
+		req["action_name"] = "QR_MULTIPLE_CARD"
+		req["action_info"] = map[string]interface{}{
+			"multiple_card": map[string]interface{}{"card_list": cards},
+		}
+	}
+	res := new(QRCodeResult)
+	if err := card.post(createQRCodeURL, req, res, "CreateCardQRCode"); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CreateLandingPage 创建货架，返回货架链接与货架ID
+func (card *Card) CreateLandingPage(page *LandingPage) (url string, pageID int64, err error) {
+	var res struct {
+		util.CommonError
+		URL    string `json:"url"`
+		PageID int64  `json:"page_id"`
+	}
+	err = card.post(createLandingPageURL, page, &res, "CreateLandingPage")
+	return res.URL, res.PageID, err
+}
@@ -0,0 +1,48 @@
+package card
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// matchBody 校验请求体的JSON内容
+func matchBody(t *testing.T, expected string) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		var got, want interface{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal([]byte(expected), &want); err != nil {
+			return false, err
+		}
+		return assert.Equal(t, want, got), nil
+	}
+}
+
+func TestCreateQRCode(t *testing.T) {
+	defer gock.Off()
+	gock.New(createQRCodeURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"action_name":"QR_CARD","expire_seconds":1800,"action_info":{"card":{"card_id":"card1","outer_str":"scene"}}}`)).
+		Reply(200).JSON(map[string]interface{}{"ticket": "ticket1", "url": "https://weixin.qq.com/q/1"})
+	gock.New(createQRCodeURL).
+		AddMatcher(matchBody(t, `{"action_name":"QR_MULTIPLE_CARD","action_info":{"multiple_card":{"card_list":[{"card_id":"card1"},{"card_id":"card2","code":"code2"}]}}}`)).
+		Reply(200).JSON(map[string]interface{}{"ticket": "ticket2"})
+
+	card := newTestCard()
+	res, err := card.CreateQRCode(1800, &QRCard{CardID: "card1", OuterStr: "scene"})
+	assert.Nil(t, err)
+	assert.Equal(t, "ticket1", res.Ticket)
+
+	res, err = card.CreateQRCode(0, &QRCard{CardID: "card1"}, &QRCard{CardID: "card2", Code: "code2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "ticket2", res.Ticket)
+	assert.True(t, gock.IsDone())
+
+	//没有卡券时不发起请求
+	_, err = card.CreateQRCode(0)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,156 @@
+package comment
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	openURL        = "https://api.weixin.qq.com/cgi-bin/comment/open"
+	closeURL       = "https://api.weixin.qq.com/cgi-bin/comment/close"
+	listURL        = "https://api.weixin.qq.com/cgi-bin/comment/list"
+	markElectURL   = "https://api.weixin.qq.com/cgi-bin/comment/markelect"
+	unmarkElectURL = "https://api.weixin.qq.com/cgi-bin/comment/unmarkelect"
+	deleteURL      = "https://api.weixin.qq.com/cgi-bin/comment/delete"
+	replyAddURL    = "https://api.weixin.qq.com/cgi-bin/comment/reply/add"
+	replyDeleteURL = "https://api.weixin.qq.com/cgi-bin/comment/reply/delete"
+)
+
+// ListLimit 每次拉取评论的最大数量
+const ListLimit = 50
+
+// Type 评论类型
+type Type int
+
+const (
+	// TypeAll 普通评论和精选评论
+	TypeAll Type = iota
+	// TypeNormal 普通评论
+	TypeNormal
+	// TypeElected 精选评论
+	TypeElected
+)
+
+// Comment 图文评论管理
+type Comment struct {
+	*context.Context
+}
+
+// NewComment init
+func NewComment(ctx *context.Context) *Comment {
+	return &Comment{
+		Context: ctx,
+	}
+}
+
+// Item 单条评论
+type Item struct {
+	UserCommentID int64  `json:"user_comment_id"` // 用户评论id
+	OpenID        string `json:"openid"`
+	CreateTime    int64  `json:"create_time"`
+	Content       string `json:"content"`
+	CommentType   int    `json:"comment_type"` // 是否精选评论，0 为即非精选，1 为精选评论
+	Reply         *Reply `json:"reply"`        // 作者回复
+}
+
+// Reply 作者回复
+type Reply struct {
+	Content    string `json:"content"`
+	CreateTime int64  `json:"create_time"`
+}
+
+// List 评论列表
+type List struct {
+	Total   int64   `json:"total"`
+	Comment []*Item `json:"comment"`
+}
+
+// article 定位一篇文章，msgDataID 为群发返回的msg_data_id，index 为多图文时的文章序号，第一篇为0
+type article struct {
+	MsgDataID int64 `json:"msg_data_id"`
+	Index     int   `json:"index"`
+}
+
+type commentReq struct {
+	article
+	UserCommentID int64 `json:"user_comment_id"`
+}
+
+// Open 打开已群发文章评论
+func (comment *Comment) Open(msgDataID int64, index int) error {
+	return comment.post(openURL, article{msgDataID, index}, "OpenComment")
+}
+
+// Close 关闭已群发文章评论
+func (comment *Comment) Close(msgDataID int64, index int) error {
+	return comment.post(closeURL, article{msgDataID, index}, "CloseComment")
+}
+
+// List 查看指定文章的评论数据，begin 为起始位置，count 不超过50
+func (comment *Comment) List(msgDataID int64, index int, begin, count int, commentType Type) (*List, error) {
+	accessToken, err := comment.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	req := struct {
+		article
+		Begin int  `json:"begin"`
+		Count int  `json:"count"`
+		Type  Type `json:"type"`
+	}{article{msgDataID, index}, begin, count, commentType}
+	response, err := util.PostJSON(fmt.Sprintf("%s?access_token=%s", listURL, accessToken), req)
+	if err != nil {
+		return nil, err
+	}
+	var res struct {
+		util.CommonError
+		List
+	}
+	if err = util.DecodeWithError(response, &res, "ListComment"); err != nil {
+		return nil, err
+	}
+	return &res.List, nil
+}
+
+// MarkElect 将评论标记精选
+func (comment *Comment) MarkElect(msgDataID int64, index int, userCommentID int64) error {
+	return comment.post(markElectURL, commentReq{article{msgDataID, index}, userCommentID}, "MarkElect")
+}
+
+// UnmarkElect 将评论取消精选
+func (comment *Comment) UnmarkElect(msgDataID int64, index int, userCommentID int64) error {
+	return comment.post(unmarkElectURL, commentReq{article{msgDataID, index}, userCommentID}, "UnmarkElect")
+}
+
+// Delete 删除评论
+func (comment *Comment) Delete(msgDataID int64, index int, userCommentID int64) error {
+	return comment.post(deleteURL, commentReq{article{msgDataID, index}, userCommentID}, "DeleteComment")
+}
+
+// AddReply 回复评论
+func (comment *Comment) AddReply(msgDataID int64, index int, userCommentID int64, content string) error {
+	req := struct {
+		commentReq
+		Content string `json:"content"`
+	}{commentReq{article{msgDataID, index}, userCommentID}, content}
+	return comment.post(replyAddURL, req, "AddReply")
+}
+
+// DeleteReply 删除回复
+func (comment *Comment) DeleteReply(msgDataID int64, index int, userCommentID int64) error {
+	return comment.post(replyDeleteURL, commentReq{article{msgDataID, index}, userCommentID}, "DeleteReply")
+}
+
+func (comment *Comment) post(apiURL string, req interface{}, apiName string) error {
+	accessToken, err := comment.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	response, err := util.PostJSON(fmt.Sprintf("%s?access_token=%s", apiURL, accessToken), req)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithCommonError(response, apiName)
+}
@@ -0,0 +1,80 @@
+package comment
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func matchBody(t *testing.T, expected string) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		var got, want interface{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal([]byte(expected), &want); err != nil {
+			return false, err
+		}
+		return assert.Equal(t, want, got), nil
+	}
+}
+
+func newTestComment() *Comment {
+	return NewComment(&context.Context{AccessTokenHandle: mockAccessToken{}})
+}
+
+func TestList(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/comment/list").
+		MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"msg_data_id":100,"index":1,"begin":0,"count":50,"type":2}`)).
+		Reply(200).JSON(map[string]interface{}{
+		"errcode": 0, "errmsg": "ok", "total": 1,
+		"comment": []map[string]interface{}{{
+			"user_comment_id": 1, "openid": "openid1", "create_time": 1600000000, "content": "hi", "comment_type": 1,
+			"reply": map[string]interface{}{"content": "thanks", "create_time": 1600000001},
+		}},
+	})
+
+	list, err := newTestComment().List(100, 1, 0, ListLimit, TypeElected)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), list.Total)
+	assert.Len(t, list.Comment, 1)
+	assert.Equal(t, int64(1), list.Comment[0].UserCommentID)
+	assert.Equal(t, "thanks", list.Comment[0].Reply.Content)
+	assert.True(t, gock.IsDone())
+}
+
+func TestManage(t *testing.T) {
+	defer gock.Off()
+	ok := map[string]interface{}{"errcode": 0, "errmsg": "ok"}
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/comment/open").
+		AddMatcher(matchBody(t, `{"msg_data_id":100,"index":0}`)).
+		Reply(200).JSON(ok)
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/comment/markelect").
+		AddMatcher(matchBody(t, `{"msg_data_id":100,"index":0,"user_comment_id":1}`)).
+		Reply(200).JSON(ok)
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/comment/reply/add").
+		AddMatcher(matchBody(t, `{"msg_data_id":100,"index":0,"user_comment_id":1,"content":"thanks"}`)).
+		Reply(200).JSON(ok)
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/comment/delete").
+		AddMatcher(matchBody(t, `{"msg_data_id":100,"index":0,"user_comment_id":2}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 88000, "errmsg": "without comment privilege"})
+
+	comment := newTestComment()
+	assert.Nil(t, comment.Open(100, 0))
+	assert.Nil(t, comment.MarkElect(100, 0, 1))
+	assert.Nil(t, comment.AddReply(100, 0, 1, "thanks"))
+	assert.NotNil(t, comment.Delete(100, 0, 2))
+	assert.True(t, gock.IsDone())
+}
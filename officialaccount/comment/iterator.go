@@ -0,0 +1,72 @@
+package comment
+
+// Iterator 逐条迭代文章的全部评论
+//
+//	it := comment.Iterate(msgDataID, 0, comment.TypeAll)
+//	for it.Next() {
+//		fmt.Println(it.Comment().Content)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	comment     *Comment
+	msgDataID   int64
+	index       int
+	commentType Type
+
+	begin   int
+	total   int64
+	buf     []*Item
+	current *Item
+	done    bool
+	err     error
+}
+
+// Iterate 返回文章评论的迭代器
+func (comment *Comment) Iterate(msgDataID int64, index int, commentType Type) *Iterator {
+	return &Iterator{
+		comment:     comment,
+		msgDataID:   msgDataID,
+		index:       index,
+		commentType: commentType,
+	}
+}
+
+// Next 移动到下一条评论，没有更多评论或出错时返回false
+func (it *Iterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		list, err := it.comment.List(it.msgDataID, it.index, it.begin, ListLimit, it.commentType)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.total = list.Total
+		it.buf = list.Comment
+		it.begin += len(list.Comment)
+		if len(list.Comment) < ListLimit || int64(it.begin) >= list.Total {
+			it.done = true
+		}
+	}
+	it.current = it.buf[0]
+	it.buf = it.buf[1:]
+	return true
+}
+
+// Comment 当前评论
+func (it *Iterator) Comment() *Item {
+	return it.current
+}
+
+// Total 评论总数，在第一次调用 Next 之后有效
+func (it *Iterator) Total() int64 {
+	return it.total
+}
+
+// Err 迭代过程中的错误
+func (it *Iterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,54 @@
+package comment
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockListPage(t *testing.T, begin, count int, total int64) {
+	items := make([]map[string]interface{}, count)
+	for i := range items {
+		items[i] = map[string]interface{}{"user_comment_id": begin + i, "content": fmt.Sprintf("comment%d", begin+i)}
+	}
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/comment/list").
+		AddMatcher(matchBody(t, fmt.Sprintf(`{"msg_data_id":100,"index":0,"begin":%d,"count":%d,"type":0}`, begin, ListLimit))).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "total": total, "comment": items})
+}
+
+func TestIterator(t *testing.T) {
+	defer gock.Off()
+	mockListPage(t, 0, ListLimit, 60)
+	mockListPage(t, ListLimit, 10, 60)
+
+	it := newTestComment().Iterate(100, 0, TypeAll)
+	var got []int64
+	for it.Next() {
+		got = append(got, it.Comment().UserCommentID)
+	}
+	assert.Nil(t, it.Err())
+	assert.Len(t, got, 60)
+	assert.Equal(t, int64(0), got[0])
+	assert.Equal(t, int64(59), got[59])
+	assert.Equal(t, int64(60), it.Total())
+	assert.True(t, gock.IsDone())
+}
+
+func TestIteratorError(t *testing.T) {
+	defer gock.Off()
+	mockListPage(t, 0, ListLimit, 60)
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/comment/list").
+		Reply(200).JSON(map[string]interface{}{"errcode": 45009, "errmsg": "api freq out of limit"})
+
+	it := newTestComment().Iterate(100, 0, TypeAll)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	assert.Equal(t, ListLimit, count)
+	assert.NotNil(t, it.Err())
+	assert.False(t, it.Next())
+	assert.True(t, gock.IsDone())
+}
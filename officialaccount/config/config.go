@@ -6,9 +6,10 @@ import (
 
 //Config config for 微信公众号
 type Config struct {
-	AppID          string `json:"app_id"`           //appid
-	AppSecret      string `json:"app_secret"`       //appsecret
-	Token          string `json:"token"`            //token
-	EncodingAESKey string `json:"encoding_aes_key"` //EncodingAESKey
-	Cache          cache.Cache
+	AppID               string   `json:"app_id"`                 //appid
+	AppSecret           string   `json:"app_secret"`             //appsecret
+	Token               string   `json:"token"`                  //token
+	EncodingAESKey      string   `json:"encoding_aes_key"`       //EncodingAESKey
+	PrevEncodingAESKeys []string `json:"prev_encoding_aes_keys"` //轮换EncodingAESKey期间的历史key，当前key解密失败时依次尝试
+	Cache               cache.Cache
 }
@@ -0,0 +1,122 @@
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/silenceper/wechat/v2/credential"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultTTL 会话默认超时时间
+const DefaultTTL = 30 * time.Minute
+
+// Handler 带会话状态的消息处理方法
+type Handler func(sess *Session, msg message.MixMessage) *message.Reply
+
+// Conversation 多轮对话的会话状态管理，按 appID + openID 保存在cache中
+// 会话的读取与保存不是原子操作，同一用户的消息需要串行处理，否则并发保存时可能互相覆盖
+type Conversation struct {
+	*context.Context
+
+	appID   string
+	ttl     time.Duration
+	manager *message.Manager
+
+	// OnTimeout 用户的会话已超时时回调，返回非nil的回复将直接回复给用户，否则继续交给Handler处理
+	OnTimeout func(sess *Session, msg message.MixMessage) *message.Reply
+}
+
+// NewConversation 实例化，ttl<=0 时使用 DefaultTTL
+func NewConversation(context *context.Context, ttl time.Duration) *Conversation {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Conversation{
+		Context: context,
+		appID:   context.AppID,
+		ttl:     ttl,
+		manager: message.NewMessageManager(context),
+	}
+}
+
+// SetAppID 设置会话所属的appID，第三方平台代公众号处理时应设置为授权方appID
+func (conv *Conversation) SetAppID(appID string) {
+	conv.appID = appID
+}
+
+// SetMessageManager 自定义发送客服消息的manager
+func (conv *Conversation) SetMessageManager(manager *message.Manager) {
+	conv.manager = manager
+}
+
+func (conv *Conversation) cacheKey(openID string) string {
+	return fmt.Sprintf("%s_conversation_%s_%s", credential.CacheKeyOfficialAccountPrefix, conv.appID, openID)
+}
+
+// Load 读取用户的会话，不存在时返回空会话；异步回复（客服消息）时也可通过该方法读取并更新状态
+func (conv *Conversation) Load(openID string) (*Session, error) {
+	sess := &Session{
+		conv:  conv,
+		State: conv.newState(openID),
+	}
+	val := conv.Cache.Get(conv.cacheKey(openID))
+	if val == nil {
+		return sess, nil
+	}
+	str, ok := val.(string)
+	if !ok {
+		return sess, fmt.Errorf("conversation state type error: %T", val)
+	}
+	state := new(State)
+	if err := json.Unmarshal([]byte(str), state); err != nil {
+		return sess, fmt.Errorf("conversation state unmarshal error, err=%v", err)
+	}
+	if state.ExpireAt > 0 && state.ExpireAt < time.Now().Unix() {
+		sess.expired = true
+		sess.previous = state
+		sess.changed = true
+		return sess, nil
+	}
+	sess.State = state
+	return sess, nil
+}
+
+// Clear 清除用户的会话
+func (conv *Conversation) Clear(openID string) error {
+	return conv.Cache.Delete(conv.cacheKey(openID))
+}
+
+func (conv *Conversation) newState(openID string) *State {
+	return &State{
+		AppID:  conv.appID,
+		OpenID: openID,
+		Data:   map[string]string{},
+	}
+}
+
+// Handle 包装带会话状态的handler，可直接传给 server.SetMessageHandler
+// handler 返回后会自动保存会话状态
+func (conv *Conversation) Handle(handler Handler) func(message.MixMessage) *message.Reply {
+	return func(msg message.MixMessage) *message.Reply {
+		openID := string(msg.FromUserName)
+		sess, err := conv.Load(openID)
+		if err != nil {
+			log.Errorf("load conversation failed, openID=%s, err=%v", openID, err)
+		}
+		var reply *message.Reply
+		if sess.Expired() && conv.OnTimeout != nil {
+			reply = conv.OnTimeout(sess, msg)
+		}
+		if reply == nil {
+			reply = handler(sess, msg)
+		}
+		if err = sess.Save(); err != nil {
+			log.Errorf("save conversation failed, openID=%s, err=%v", openID, err)
+		}
+		return reply
+	}
+}
@@ -0,0 +1,80 @@
+package conversation
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/officialaccount/config"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConversation(ttl time.Duration) *Conversation {
+	return NewConversation(&context.Context{
+		Config: &config.Config{AppID: "wx123", Cache: cache.NewMemory()},
+	}, ttl)
+}
+
+func TestExpectNextTimeoutPersisted(t *testing.T) {
+	conv := newTestConversation(time.Minute)
+	sess, err := conv.Load("openid")
+	assert.Nil(t, err)
+	sess.Transition("bind")
+	sess.ExpectNext("phone", time.Hour)
+	assert.Nil(t, sess.Save())
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), sess.ExpireAt, 1)
+
+	//再次读取并保存时沿用 ExpectNext 设置的超时时间
+	sess, err = conv.Load("openid")
+	assert.Nil(t, err)
+	assert.Equal(t, "phone", sess.Expect)
+	sess.Set("phone", "13800000000")
+	assert.Nil(t, sess.Save())
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), sess.ExpireAt, 1)
+
+	//timeout<=0 时恢复默认超时时间
+	sess.ExpectNext("code", 0)
+	assert.Nil(t, sess.Save())
+	assert.InDelta(t, time.Now().Add(time.Minute).Unix(), sess.ExpireAt, 1)
+}
+
+func TestLoadExpired(t *testing.T) {
+	conv := newTestConversation(time.Minute)
+	state := conv.newState("openid")
+	state.Step = "bind"
+	state.ExpireAt = time.Now().Add(-time.Second).Unix()
+	data, _ := json.Marshal(state)
+	assert.Nil(t, conv.Cache.Set(conv.cacheKey("openid"), string(data), time.Minute))
+
+	sess, err := conv.Load("openid")
+	assert.Nil(t, err)
+	assert.True(t, sess.Expired())
+	assert.Equal(t, "bind", sess.Previous().Step)
+	assert.Equal(t, "", sess.Step)
+
+	//超时后保存空会话会清除缓存
+	assert.Nil(t, sess.Save())
+	assert.Nil(t, conv.Cache.Get(conv.cacheKey("openid")))
+}
+
+func TestHandleEnd(t *testing.T) {
+	conv := newTestConversation(time.Minute)
+	msg := message.MixMessage{}
+	msg.FromUserName = "openid"
+
+	handle := conv.Handle(func(sess *Session, msg message.MixMessage) *message.Reply {
+		if sess.Step == "" {
+			sess.Transition("started")
+			return nil
+		}
+		sess.End()
+		return nil
+	})
+	handle(msg)
+	assert.NotNil(t, conv.Cache.Get(conv.cacheKey("openid")))
+	handle(msg)
+	assert.Nil(t, conv.Cache.Get(conv.cacheKey("openid")))
+}
@@ -0,0 +1,113 @@
+package conversation
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+)
+
+// State 会话状态
+type State struct {
+	AppID     string            `json:"app_id"`
+	OpenID    string            `json:"open_id"`
+	Step      string            `json:"step"`       // 当前所处步骤
+	Expect    string            `json:"expect"`     // 期望用户下一条消息提供的内容，如 order_no、phone
+	Data      map[string]string `json:"data"`       // 流程中收集的数据
+	Timeout   int64             `json:"timeout"`    // ExpectNext 设置的超时秒数，为0时使用默认超时时间
+	ExpireAt  int64             `json:"expire_at"`  // 会话超时时间戳
+	UpdatedAt int64             `json:"updated_at"` // 最后更新时间戳
+}
+
+// Session 单个用户的会话
+type Session struct {
+	*State
+
+	conv     *Conversation
+	expired  bool
+	previous *State
+	changed  bool
+	ended    bool
+}
+
+// Expired 用户之前的会话是否已超时，超时后会话已被重置
+func (sess *Session) Expired() bool {
+	return sess.expired
+}
+
+// Previous 返回已超时的会话状态，未超时时为nil
+func (sess *Session) Previous() *State {
+	return sess.previous
+}
+
+// Transition 切换到下一步骤，并清除当前的期望
+func (sess *Session) Transition(step string) {
+	sess.Step = step
+	sess.Expect = ""
+	sess.changed = true
+}
+
+// ExpectNext 设置期望用户下一条消息提供的内容，timeout>0 时覆盖默认超时时间，
+// 该超时时间随会话一起保存，之后的每次保存都沿用，直到再次调用 ExpectNext
+func (sess *Session) ExpectNext(expect string, timeout time.Duration) {
+	sess.Expect = expect
+	sess.Timeout = 0
+	if timeout > 0 {
+		sess.Timeout = int64(timeout.Seconds())
+	}
+	sess.changed = true
+}
+
+// Set 保存流程数据
+func (sess *Session) Set(key, value string) {
+	if sess.Data == nil {
+		sess.Data = map[string]string{}
+	}
+	sess.Data[key] = value
+	sess.changed = true
+}
+
+// Get 读取流程数据
+func (sess *Session) Get(key string) string {
+	return sess.Data[key]
+}
+
+// End 结束会话，保存时将清除会话状态
+func (sess *Session) End() {
+	sess.ended = true
+	sess.changed = true
+}
+
+// Save 保存会话状态，在 Conversation.Handle 之外使用时需要手动调用
+// 注意 Load 与 Save 之间不加锁，同一用户的多条消息并发处理时，后保存的状态会覆盖先保存的状态
+func (sess *Session) Save() error {
+	if !sess.changed {
+		return nil
+	}
+	if sess.ended || (sess.Step == "" && sess.Expect == "" && len(sess.Data) == 0) {
+		return sess.conv.Clear(sess.OpenID)
+	}
+	timeout := sess.conv.ttl
+	if sess.Timeout > 0 {
+		timeout = time.Duration(sess.Timeout) * time.Second
+	}
+	now := time.Now()
+	sess.UpdatedAt = now.Unix()
+	sess.ExpireAt = now.Add(timeout).Unix()
+	data, err := json.Marshal(sess.State)
+	if err != nil {
+		return err
+	}
+	//多保留一个ttl，以便用户超时后再次发消息时能够感知到超时
+	err = sess.conv.Cache.Set(sess.conv.cacheKey(sess.OpenID), string(data), timeout+sess.conv.ttl)
+	if err == nil {
+		sess.changed = false
+	}
+	return err
+}
+
+// SendCustomerMessage 以客服消息的方式异步回复当前用户
+func (sess *Session) SendCustomerMessage(msg *message.CustomerMessage) error {
+	msg.ToUser = sess.OpenID
+	return sess.conv.manager.Send(msg)
+}
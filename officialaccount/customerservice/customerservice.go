@@ -0,0 +1,175 @@
+package customerservice
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	//客服帐号管理
+	//https://developers.weixin.qq.com/doc/offiaccount/Customer_Service/Customer_Service_Management.html
+	kfAccountAddURL           = "https://api.weixin.qq.com/customservice/kfaccount/add"
+	kfAccountUpdateURL        = "https://api.weixin.qq.com/customservice/kfaccount/update"
+	kfAccountDelURL           = "https://api.weixin.qq.com/customservice/kfaccount/del"
+	kfAccountInviteURL        = "https://api.weixin.qq.com/customservice/kfaccount/inviteworker"
+	kfAccountUploadHeadImgURL = "https://api.weixin.qq.com/customservice/kfaccount/uploadheadimg"
+	kfListURL                 = "https://api.weixin.qq.com/cgi-bin/customservice/getkflist"
+	kfOnlineListURL           = "https://api.weixin.qq.com/cgi-bin/customservice/getonlinekflist"
+	//客服输入状态
+	typingURL = "https://api.weixin.qq.com/cgi-bin/message/custom/typing"
+)
+
+// CustomerService 客服管理
+type CustomerService struct {
+	*context.Context
+}
+
+// NewCustomerService 实例化
+func NewCustomerService(context *context.Context) *CustomerService {
+	return &CustomerService{Context: context}
+}
+
+// KfInfo 客服基本信息
+type KfInfo struct {
+	KfAccount        string `json:"kf_account"`         // 完整客服帐号，格式为：帐号前缀@公众号微信号
+	KfNick           string `json:"kf_nick"`            // 客服昵称
+	KfID             int64  `json:"kf_id"`              // 客服编号
+	KfHeadImgURL     string `json:"kf_headimgurl"`      // 客服头像
+	KfWx             string `json:"kf_wx"`              // 如果客服帐号已绑定了客服人员微信号， 则此处显示微信号
+	InviteWx         string `json:"invite_wx"`          // 如果客服帐号尚未绑定微信号，但是已经发起了一个绑定邀请， 则此处显示绑定邀请的微信号
+	InviteExpireTime int64  `json:"invite_expire_time"` // 如果客服帐号尚未绑定微信号，但是已经发起过一个绑定邀请， 邀请的过期时间，为unix 时间戳
+	InviteStatus     string `json:"invite_status"`      // 邀请的状态，有等待确认“waiting”，被拒绝“rejected”， 过期“expired”
+}
+
+// KfOnlineInfo 在线客服信息
+type KfOnlineInfo struct {
+	KfAccount    string `json:"kf_account"`
+	Status       int    `json:"status"` // 客服在线状态，目前为：1、web 在线
+	KfID         int64  `json:"kf_id"`
+	AcceptedCase int    `json:"accepted_case"` // 客服当前正在接待的会话数
+}
+
+type resKfList struct {
+	util.CommonError
+
+	KfList []*KfInfo `json:"kf_list"`
+}
+
+type resKfOnlineList struct {
+	util.CommonError
+
+	KfOnlineList []*KfOnlineInfo `json:"kf_online_list"`
+}
+
+// postJSON 发送请求并按CommonError解析
+func (cs *CustomerService) postJSON(apiURL string, req interface{}, apiName string) error {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", apiURL, accessToken)
+	response, err := util.PostJSON(uri, req)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithCommonError(response, apiName)
+}
+
+// AddAccount 添加客服帐号，kfAccount 格式为：帐号前缀@公众号微信号
+func (cs *CustomerService) AddAccount(kfAccount, nickname string) error {
+	return cs.postJSON(kfAccountAddURL, map[string]string{
+		"kf_account": kfAccount,
+		"nickname":   nickname,
+	}, "AddKfAccount")
+}
+
+// UpdateAccount 设置客服信息
+func (cs *CustomerService) UpdateAccount(kfAccount, nickname string) error {
+	return cs.postJSON(kfAccountUpdateURL, map[string]string{
+		"kf_account": kfAccount,
+		"nickname":   nickname,
+	}, "UpdateKfAccount")
+}
+
+// InviteWorker 邀请绑定客服帐号，inviteWx 为接收绑定邀请的客服微信号
+func (cs *CustomerService) InviteWorker(kfAccount, inviteWx string) error {
+	return cs.postJSON(kfAccountInviteURL, map[string]string{
+		"kf_account": kfAccount,
+		"invite_wx":  inviteWx,
+	}, "InviteKfWorker")
+}
+
+// DeleteAccount 删除客服帐号
+func (cs *CustomerService) DeleteAccount(kfAccount string) error {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s&kf_account=%s", kfAccountDelURL, accessToken, url.QueryEscape(kfAccount))
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithCommonError(response, "DeleteKfAccount")
+}
+
+// UploadHeadImg 上传客服头像，头像图片文件必须是jpg格式，推荐使用640*640大小的图片
+func (cs *CustomerService) UploadHeadImg(kfAccount, filename string) error {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s&kf_account=%s", kfAccountUploadHeadImgURL, accessToken, url.QueryEscape(kfAccount))
+	response, err := util.PostFile("media", filename, uri)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithCommonError(response, "UploadKfHeadImg")
+}
+
+// List 获取所有客服基本信息
+func (cs *CustomerService) List() ([]*KfInfo, error) {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", kfListURL, accessToken)
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	res := &resKfList{}
+	err = util.DecodeWithError(response, res, "GetKfList")
+	return res.KfList, err
+}
+
+// OnlineList 获取在线客服的接待信息
+func (cs *CustomerService) OnlineList() ([]*KfOnlineInfo, error) {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", kfOnlineListURL, accessToken)
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	res := &resKfOnlineList{}
+	err = util.DecodeWithError(response, res, "GetOnlineKfList")
+	return res.KfOnlineList, err
+}
+
+// Typing 下发或取消客服输入状态，用户在与公众号交互的48小时内有效
+func (cs *CustomerService) Typing(openID string, typing bool) error {
+	command := "CancelTyping"
+	if typing {
+		command = "Typing"
+	}
+	return cs.postJSON(typingURL, map[string]string{
+		"touser":  openID,
+		"command": command,
+	}, "Typing")
+}
@@ -0,0 +1,88 @@
+package customerservice
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func newTestCustomerService() *CustomerService {
+	return NewCustomerService(&context.Context{AccessTokenHandle: mockAccessToken{}})
+}
+
+// matchBody 校验请求体的JSON内容
+func matchBody(t *testing.T, expected string) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		var got, want interface{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal([]byte(expected), &want); err != nil {
+			return false, err
+		}
+		return assert.Equal(t, want, got), nil
+	}
+}
+
+func TestAccount(t *testing.T) {
+	defer gock.Off()
+	gock.New(kfAccountAddURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"kf_account":"kf1@test","nickname":"客服1"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New(kfAccountInviteURL).
+		AddMatcher(matchBody(t, `{"kf_account":"kf1@test","invite_wx":"wx_worker"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 65400, "errmsg": "please enable new custom service"})
+	gock.New(kfAccountDelURL).MatchParam("kf_account", "kf1@test").
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	cs := newTestCustomerService()
+	assert.Nil(t, cs.AddAccount("kf1@test", "客服1"))
+	assert.NotNil(t, cs.InviteWorker("kf1@test", "wx_worker"))
+	assert.Nil(t, cs.DeleteAccount("kf1@test"))
+	assert.True(t, gock.IsDone())
+}
+
+func TestList(t *testing.T) {
+	defer gock.Off()
+	gock.New(kfListURL).MatchParam("access_token", "mock-ak").
+		Reply(200).JSON(map[string]interface{}{
+		"kf_list": []map[string]interface{}{{"kf_account": "kf1@test", "kf_nick": "客服1", "kf_id": 1001}},
+	})
+	gock.New(kfOnlineListURL).
+		Reply(200).JSON(map[string]interface{}{
+		"kf_online_list": []map[string]interface{}{{"kf_account": "kf1@test", "status": 1, "kf_id": 1001, "accepted_case": 2}},
+	})
+
+	cs := newTestCustomerService()
+	list, err := cs.List()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1001), list[0].KfID)
+
+	online, err := cs.OnlineList()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, online[0].AcceptedCase)
+	assert.True(t, gock.IsDone())
+}
+
+func TestTyping(t *testing.T) {
+	defer gock.Off()
+	gock.New(typingURL).AddMatcher(matchBody(t, `{"touser":"openid1","command":"Typing"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New(typingURL).AddMatcher(matchBody(t, `{"touser":"openid1","command":"CancelTyping"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	cs := newTestCustomerService()
+	assert.Nil(t, cs.Typing("openid1", true))
+	assert.Nil(t, cs.Typing("openid1", false))
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,37 @@
+package customerservice
+
+import (
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+)
+
+// SessionEvent 客服会话事件：接入、关闭、转接
+type SessionEvent struct {
+	Event         message.EventType
+	OpenID        string
+	CreateTime    int64
+	KfAccount     string // 接入或关闭会话的客服
+	FromKfAccount string // 转接前的客服
+	ToKfAccount   string // 转接后的客服
+	CloseType     string // 关闭会话的方式
+}
+
+// ParseSessionEvent 解析客服会话事件，不是客服会话事件时返回false
+func ParseSessionEvent(msg message.MixMessage) (*SessionEvent, bool) {
+	if msg.MsgType != message.MsgTypeEvent {
+		return nil, false
+	}
+	switch msg.Event {
+	case message.EventKfCreateSession, message.EventKfCloseSession, message.EventKfSwitchSession:
+	default:
+		return nil, false
+	}
+	return &SessionEvent{
+		Event:         msg.Event,
+		OpenID:        string(msg.FromUserName),
+		CreateTime:    msg.CreateTime,
+		KfAccount:     msg.KfAccount,
+		FromKfAccount: msg.FromKfAccount,
+		ToKfAccount:   msg.ToKfAccount,
+		CloseType:     msg.CloseType,
+	}, true
+}
@@ -0,0 +1,34 @@
+package customerservice
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSessionEvent(t *testing.T) {
+	raw := `<xml>
+<ToUserName><![CDATA[touser]]></ToUserName>
+<FromUserName><![CDATA[fromuser]]></FromUserName>
+<CreateTime>1399197672</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[kf_switch_session]]></Event>
+<FromKfAccount><![CDATA[test1@test]]></FromKfAccount>
+<ToKfAccount><![CDATA[test2@test]]></ToKfAccount>
+</xml>`
+	var msg message.MixMessage
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &msg))
+
+	e, ok := ParseSessionEvent(msg)
+	assert.True(t, ok)
+	assert.Equal(t, message.EventType(message.EventKfSwitchSession), e.Event)
+	assert.Equal(t, "fromuser", e.OpenID)
+	assert.Equal(t, "test1@test", e.FromKfAccount)
+	assert.Equal(t, "test2@test", e.ToKfAccount)
+
+	msg.Event = message.EventClick
+	_, ok = ParseSessionEvent(msg)
+	assert.False(t, ok)
+}
@@ -0,0 +1,80 @@
+package customerservice
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	//获取聊天记录
+	//https://developers.weixin.qq.com/doc/offiaccount/Customer_Service/Obtain_chat_transcript.html
+	msgRecordListURL = "https://api.weixin.qq.com/customservice/msgrecord/getmsglist"
+)
+
+// MsgRecordRequest 获取聊天记录请求参数
+type MsgRecordRequest struct {
+	StartTime int64 `json:"starttime"` // 起始时间，unix时间戳
+	EndTime   int64 `json:"endtime"`   // 结束时间，unix时间戳，每次查询时段不能超过24小时
+	MsgID     int64 `json:"msgid"`     // 消息id顺序从小到大，从1开始
+	Number    int   `json:"number"`    // 每次获取条数，最多10000条
+}
+
+// MsgRecord 聊天记录
+type MsgRecord struct {
+	OpenID   string `json:"openid"`
+	OperCode int    `json:"opercode"` // 操作码，2002（客服发送信息），2003（客服接收消息）
+	Text     string `json:"text"`
+	Time     int64  `json:"time"`
+	Worker   string `json:"worker"` // 完整客服帐号
+}
+
+// MsgRecordList 聊天记录列表
+type MsgRecordList struct {
+	util.CommonError
+
+	RecordList []*MsgRecord `json:"recordlist"`
+	Number     int          `json:"number"`
+	MsgID      int64        `json:"msgid"`
+}
+
+// GetMsgRecordList 获取聊天记录
+func (cs *CustomerService) GetMsgRecordList(req *MsgRecordRequest) (*MsgRecordList, error) {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", msgRecordListURL, accessToken)
+	response, err := util.PostJSON(uri, req)
+	if err != nil {
+		return nil, err
+	}
+	res := &MsgRecordList{}
+	err = util.DecodeWithError(response, res, "GetMsgRecordList")
+	return res, err
+}
+
+// ExportMsgRecords 导出时段内的全部聊天记录，按msgid翻页直到取完
+func (cs *CustomerService) ExportMsgRecords(startTime, endTime int64, fn func(records []*MsgRecord) error) error {
+	req := &MsgRecordRequest{
+		StartTime: startTime,
+		EndTime:   endTime,
+		MsgID:     1,
+		Number:    10000,
+	}
+	for {
+		res, err := cs.GetMsgRecordList(req)
+		if err != nil {
+			return err
+		}
+		if len(res.RecordList) > 0 {
+			if err = fn(res.RecordList); err != nil {
+				return err
+			}
+		}
+		if res.Number < req.Number || res.MsgID == 0 {
+			return nil
+		}
+		req.MsgID = res.MsgID
+	}
+}
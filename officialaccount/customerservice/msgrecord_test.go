@@ -0,0 +1,50 @@
+package customerservice
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestExportMsgRecords(t *testing.T) {
+	defer gock.Off()
+	records := make([]map[string]interface{}, 10000)
+	for i := range records {
+		records[i] = map[string]interface{}{"openid": "openid1", "opercode": 2002, "text": "hi", "time": 1600000000, "worker": "kf1@test"}
+	}
+	//第一页取满，按返回的msgid继续取下一页，直到不足一页
+	gock.New(msgRecordListURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"starttime":1600000000,"endtime":1600003600,"msgid":1,"number":10000}`)).
+		Reply(200).JSON(map[string]interface{}{"recordlist": records, "number": 10000, "msgid": 10001})
+	gock.New(msgRecordListURL).
+		AddMatcher(matchBody(t, `{"starttime":1600000000,"endtime":1600003600,"msgid":10001,"number":10000}`)).
+		Reply(200).JSON(map[string]interface{}{"recordlist": records[:1], "number": 1, "msgid": 10002})
+
+	var count int
+	err := newTestCustomerService().ExportMsgRecords(1600000000, 1600003600, func(records []*MsgRecord) error {
+		count += len(records)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 10001, count)
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportMsgRecordsStopOnError(t *testing.T) {
+	defer gock.Off()
+	gock.New(msgRecordListURL).
+		Reply(200).JSON(map[string]interface{}{
+		"recordlist": []map[string]interface{}{{"openid": "openid1"}},
+		"number":     10000,
+		"msgid":      2,
+	})
+
+	stop := errors.New("stop")
+	err := newTestCustomerService().ExportMsgRecords(1600000000, 1600003600, func(records []*MsgRecord) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.True(t, gock.IsDone())
+}
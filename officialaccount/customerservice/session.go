@@ -0,0 +1,116 @@
+package customerservice
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	//会话控制
+	//https://developers.weixin.qq.com/doc/offiaccount/Customer_Service/Session_control.html
+	kfSessionCreateURL   = "https://api.weixin.qq.com/customservice/kfsession/create"
+	kfSessionCloseURL    = "https://api.weixin.qq.com/customservice/kfsession/close"
+	kfSessionGetURL      = "https://api.weixin.qq.com/customservice/kfsession/getsession"
+	kfSessionListURL     = "https://api.weixin.qq.com/customservice/kfsession/getsessionlist"
+	kfSessionWaitCaseURL = "https://api.weixin.qq.com/customservice/kfsession/getwaitcase"
+)
+
+// Session 客户的会话状态
+type Session struct {
+	util.CommonError
+
+	KfAccount  string `json:"kf_account"` // 正在接待的客服，为空表示没有人在接待
+	CreateTime int64  `json:"createtime"` // 会话接入的时间
+}
+
+// SessionItem 客服的会话
+type SessionItem struct {
+	OpenID     string `json:"openid"`
+	CreateTime int64  `json:"createtime"`
+}
+
+// WaitCaseItem 未接入会话
+type WaitCaseItem struct {
+	OpenID     string `json:"openid"`
+	LatestTime int64  `json:"latest_time"` // 粉丝的最后一条消息的时间
+}
+
+// WaitCase 未接入会话列表
+type WaitCase struct {
+	util.CommonError
+
+	Count        int             `json:"count"`
+	WaitCaseList []*WaitCaseItem `json:"waitcaselist"`
+}
+
+type resSessionList struct {
+	util.CommonError
+
+	SessionList []*SessionItem `json:"sessionlist"`
+}
+
+// CreateSession 创建会话，此接口在客服和用户之间创建一个会话
+func (cs *CustomerService) CreateSession(kfAccount, openID string) error {
+	return cs.postJSON(kfSessionCreateURL, map[string]string{
+		"kf_account": kfAccount,
+		"openid":     openID,
+	}, "CreateKfSession")
+}
+
+// CloseSession 关闭会话
+func (cs *CustomerService) CloseSession(kfAccount, openID string) error {
+	return cs.postJSON(kfSessionCloseURL, map[string]string{
+		"kf_account": kfAccount,
+		"openid":     openID,
+	}, "CloseKfSession")
+}
+
+// GetSession 获取客户会话状态
+func (cs *CustomerService) GetSession(openID string) (*Session, error) {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s&openid=%s", kfSessionGetURL, accessToken, openID)
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	res := &Session{}
+	err = util.DecodeWithError(response, res, "GetKfSession")
+	return res, err
+}
+
+// ListSession 获取客服的会话列表
+func (cs *CustomerService) ListSession(kfAccount string) ([]*SessionItem, error) {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s&kf_account=%s", kfSessionListURL, accessToken, url.QueryEscape(kfAccount))
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	res := &resSessionList{}
+	err = util.DecodeWithError(response, res, "GetKfSessionList")
+	return res.SessionList, err
+}
+
+// GetWaitCase 获取未接入会话列表，最多返回100条数据，按照来访顺序
+func (cs *CustomerService) GetWaitCase() (*WaitCase, error) {
+	accessToken, err := cs.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", kfSessionWaitCaseURL, accessToken)
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	res := &WaitCase{}
+	err = util.DecodeWithError(response, res, "GetKfWaitCase")
+	return res, err
+}
@@ -0,0 +1,43 @@
+package customerservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestSession(t *testing.T) {
+	defer gock.Off()
+	gock.New(kfSessionCreateURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"kf_account":"kf1@test","openid":"openid1"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New(kfSessionGetURL).MatchParam("openid", "openid1").
+		Reply(200).JSON(map[string]interface{}{"kf_account": "kf1@test", "createtime": 1600000000})
+	gock.New(kfSessionListURL).MatchParam("kf_account", "kf1@test").
+		Reply(200).JSON(map[string]interface{}{"sessionlist": []map[string]interface{}{{"openid": "openid1", "createtime": 1600000000}}})
+	gock.New(kfSessionWaitCaseURL).
+		Reply(200).JSON(map[string]interface{}{"count": 1, "waitcaselist": []map[string]interface{}{{"openid": "openid2", "latest_time": 1600000001}}})
+	gock.New(kfSessionCloseURL).
+		AddMatcher(matchBody(t, `{"kf_account":"kf1@test","openid":"openid1"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	cs := newTestCustomerService()
+	assert.Nil(t, cs.CreateSession("kf1@test", "openid1"))
+
+	session, err := cs.GetSession("openid1")
+	assert.Nil(t, err)
+	assert.Equal(t, "kf1@test", session.KfAccount)
+
+	list, err := cs.ListSession("kf1@test")
+	assert.Nil(t, err)
+	assert.Equal(t, "openid1", list[0].OpenID)
+
+	waitCase, err := cs.GetWaitCase()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, waitCase.Count)
+	assert.Equal(t, "openid2", waitCase.WaitCaseList[0].OpenID)
+
+	assert.Nil(t, cs.CloseSession("kf1@test", "openid1"))
+	assert.True(t, gock.IsDone())
+}
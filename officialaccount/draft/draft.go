@@ -0,0 +1,158 @@
+package draft
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	addURL      = "https://api.weixin.qq.com/cgi-bin/draft/add"
+	getURL      = "https://api.weixin.qq.com/cgi-bin/draft/get"
+	deleteURL   = "https://api.weixin.qq.com/cgi-bin/draft/delete"
+	updateURL   = "https://api.weixin.qq.com/cgi-bin/draft/update"
+	countURL    = "https://api.weixin.qq.com/cgi-bin/draft/count"
+	paginateURL = "https://api.weixin.qq.com/cgi-bin/draft/batchget"
+)
+
+// Draft 草稿箱
+type Draft struct {
+	*context.Context
+}
+
+// NewDraft init
+func NewDraft(ctx *context.Context) *Draft {
+	return &Draft{
+		Context: ctx,
+	}
+}
+
+// Article 草稿箱中的图文
+type Article struct {
+	Title              string `json:"title"`                           // 标题
+	Author             string `json:"author"`                          // 作者
+	Digest             string `json:"digest"`                          // 摘要，仅单图文有效
+	Content            string `json:"content"`                         // 图文消息的具体内容，支持HTML标签
+	ContentSourceURL   string `json:"content_source_url"`              // 原文地址
+	ThumbMediaID       string `json:"thumb_media_id"`                  // 封面图片素材id，必须是永久素材
+	ShowCoverPic       uint   `json:"show_cover_pic"`                  // 是否显示封面，0 不显示，1 显示
+	NeedOpenComment    uint   `json:"need_open_comment,omitempty"`     // 是否打开评论，0 不打开，1 打开
+	OnlyFansCanComment uint   `json:"only_fans_can_comment,omitempty"` // 是否粉丝才可评论，0 所有人可评论，1 粉丝才可评论
+	ThumbURL           string `json:"thumb_url,omitempty"`             // 封面图片地址，仅在获取时返回
+	URL                string `json:"url,omitempty"`                   // 草稿的临时链接，仅在获取时返回
+}
+
+// ArticleList 草稿列表
+type ArticleList struct {
+	TotalCount int64             `json:"total_count"` // 草稿素材的总数
+	ItemCount  int64             `json:"item_count"`  // 本次调用获取的素材的数量
+	Item       []ArticleListItem `json:"item"`
+}
+
+// ArticleListItem 草稿列表中的单个草稿
+type ArticleListItem struct {
+	MediaID string `json:"media_id"`
+	Content struct {
+		NewsItem []*Article `json:"news_item"`
+	} `json:"content"`
+	UpdateTime int64 `json:"update_time"`
+}
+
+// AddDraft 新建草稿，返回草稿的media_id
+func (draft *Draft) AddDraft(articles []*Article) (mediaID string, err error) {
+	req := map[string][]*Article{
+		"articles": articles,
+	}
+	var res struct {
+		util.CommonError
+		MediaID string `json:"media_id"`
+	}
+	err = draft.post(addURL, req, &res, "AddDraft")
+	return res.MediaID, err
+}
+
+// GetDraft 获取草稿
+func (draft *Draft) GetDraft(mediaID string) ([]*Article, error) {
+	req := map[string]string{
+		"media_id": mediaID,
+	}
+	var res struct {
+		util.CommonError
+		NewsItem []*Article `json:"news_item"`
+	}
+	if err := draft.post(getURL, req, &res, "GetDraft"); err != nil {
+		return nil, err
+	}
+	return res.NewsItem, nil
+}
+
+// DeleteDraft 删除草稿
+func (draft *Draft) DeleteDraft(mediaID string) error {
+	req := map[string]string{
+		"media_id": mediaID,
+	}
+	var res struct{ util.CommonError }
+	return draft.post(deleteURL, req, &res, "DeleteDraft")
+}
+
+// UpdateDraft 修改草稿，index 为要更新的文章在图文消息中的位置（多图文消息时，此字段才有意义），第一篇为0
+func (draft *Draft) UpdateDraft(article *Article, mediaID string, index uint) error {
+	req := map[string]interface{}{
+		"media_id": mediaID,
+		"index":    index,
+		"articles": article,
+	}
+	var res struct{ util.CommonError }
+	return draft.post(updateURL, req, &res, "UpdateDraft")
+}
+
+// CountDraft 获取草稿总数
+func (draft *Draft) CountDraft() (total int64, err error) {
+	var accessToken string
+	accessToken, err = draft.GetAccessToken()
+	if err != nil {
+		return
+	}
+	var response []byte
+	response, err = util.HTTPGet(fmt.Sprintf("%s?access_token=%s", countURL, accessToken))
+	if err != nil {
+		return
+	}
+	var res struct {
+		util.CommonError
+		Total int64 `json:"total_count"`
+	}
+	err = util.DecodeWithError(response, &res, "CountDraft")
+	return res.Total, err
+}
+
+// PaginateDraft 获取草稿列表，offset 从0开始，count 取值在1到20之间，noContent 为true时不返回content字段
+func (draft *Draft) PaginateDraft(offset, count int64, noContent bool) (list ArticleList, err error) {
+	req := map[string]interface{}{
+		"offset":     offset,
+		"count":      count,
+		"no_content": 0,
+	}
+	if noContent {
+		req["no_content"] = 1
+	}
+	var res struct {
+		util.CommonError
+		ArticleList
+	}
+	err = draft.post(paginateURL, req, &res, "PaginateDraft")
+	return res.ArticleList, err
+}
+
+func (draft *Draft) post(apiURL string, req, res interface{}, apiName string) error {
+	accessToken, err := draft.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	response, err := util.PostJSON(fmt.Sprintf("%s?access_token=%s", apiURL, accessToken), req)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithError(response, res, apiName)
+}
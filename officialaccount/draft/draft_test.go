@@ -0,0 +1,90 @@
+package draft
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func newTestDraft() *Draft {
+	return NewDraft(&context.Context{AccessTokenHandle: mockAccessToken{}})
+}
+
+// matchBody 校验请求体的JSON内容
+func matchBody(t *testing.T, expected string) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		var got, want interface{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal([]byte(expected), &want); err != nil {
+			return false, err
+		}
+		return assert.Equal(t, want, got), nil
+	}
+}
+
+func TestAddDraft(t *testing.T) {
+	defer gock.Off()
+	gock.New(addURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"articles":[{"title":"t","author":"a","digest":"d","content":"c","content_source_url":"","thumb_media_id":"thumb","show_cover_pic":1}]}`)).
+		Reply(200).JSON(map[string]interface{}{"media_id": "media1"})
+
+	mediaID, err := newTestDraft().AddDraft([]*Article{{Title: "t", Author: "a", Digest: "d", Content: "c", ThumbMediaID: "thumb", ShowCoverPic: 1}})
+	assert.Nil(t, err)
+	assert.Equal(t, "media1", mediaID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestGetAndUpdateDraft(t *testing.T) {
+	defer gock.Off()
+	gock.New(getURL).AddMatcher(matchBody(t, `{"media_id":"media1"}`)).
+		Reply(200).JSON(map[string]interface{}{"news_item": []map[string]interface{}{{"title": "t", "url": "https://mp.weixin.qq.com/s/x"}}})
+	gock.New(updateURL).AddMatcher(matchBody(t, `{"media_id":"media1","index":1,"articles":{"title":"t2","author":"","digest":"","content":"","content_source_url":"","thumb_media_id":"","show_cover_pic":0}}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New(deleteURL).AddMatcher(matchBody(t, `{"media_id":"media1"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 40007, "errmsg": "invalid media_id"})
+
+	draft := newTestDraft()
+	articles, err := draft.GetDraft("media1")
+	assert.Nil(t, err)
+	assert.Len(t, articles, 1)
+	assert.Equal(t, "https://mp.weixin.qq.com/s/x", articles[0].URL)
+
+	assert.Nil(t, draft.UpdateDraft(&Article{Title: "t2"}, "media1", 1))
+	assert.NotNil(t, draft.DeleteDraft("media1"))
+	assert.True(t, gock.IsDone())
+}
+
+func TestCountAndPaginateDraft(t *testing.T) {
+	defer gock.Off()
+	gock.New(countURL).MatchParam("access_token", "mock-ak").
+		Reply(200).JSON(map[string]interface{}{"total_count": 3})
+	gock.New(paginateURL).AddMatcher(matchBody(t, `{"offset":0,"count":20,"no_content":1}`)).
+		Reply(200).JSON(map[string]interface{}{
+		"total_count": 3,
+		"item_count":  1,
+		"item":        []map[string]interface{}{{"media_id": "media1", "update_time": 1600000000}},
+	})
+
+	draft := newTestDraft()
+	total, err := draft.CountDraft()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+
+	list, err := draft.PaginateDraft(0, 20, true)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), list.TotalCount)
+	assert.Equal(t, "media1", list.Item[0].MediaID)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,177 @@
+package freepublish
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	publishURL     = "https://api.weixin.qq.com/cgi-bin/freepublish/submit"
+	selectStateURL = "https://api.weixin.qq.com/cgi-bin/freepublish/get"
+	deleteURL      = "https://api.weixin.qq.com/cgi-bin/freepublish/delete"
+	getArticleURL  = "https://api.weixin.qq.com/cgi-bin/freepublish/getarticle"
+	paginateURL    = "https://api.weixin.qq.com/cgi-bin/freepublish/batchget"
+)
+
+// PublishStatus 发布状态
+type PublishStatus uint
+
+const (
+	// PublishStatusSuccess 发布成功
+	PublishStatusSuccess PublishStatus = iota
+	// PublishStatusPublishing 发布中
+	PublishStatusPublishing
+	// PublishStatusOriginalFail 原创失败
+	PublishStatusOriginalFail
+	// PublishStatusFail 常规失败
+	PublishStatusFail
+	// PublishStatusAuditRefused 平台审核不通过
+	PublishStatusAuditRefused
+	// PublishStatusUserDeleted 成功后用户删除所有文章
+	PublishStatusUserDeleted
+	// PublishStatusSystemBanned 成功后系统封禁所有文章
+	PublishStatusSystemBanned
+)
+
+// FreePublish 发布能力
+type FreePublish struct {
+	*context.Context
+}
+
+// NewFreePublish init
+func NewFreePublish(ctx *context.Context) *FreePublish {
+	return &FreePublish{
+		Context: ctx,
+	}
+}
+
+// PublishStatusList 发布任务的状态
+type PublishStatusList struct {
+	PublishID     int64         `json:"publish_id,string"`
+	PublishStatus PublishStatus `json:"publish_status"`
+	ArticleID     string        `json:"article_id"` // 发布成功时返回，可用于删除发布、获取已发布的图文
+	ArticleDetail struct {
+		Count uint `json:"count"`
+		Item  []struct {
+			Idx        uint   `json:"idx"`
+			ArticleURL string `json:"article_url"`
+		} `json:"item"`
+	} `json:"article_detail"`
+	FailIdx []uint `json:"fail_idx"` // 原创审核不通过或常规失败时，失败的文章编号
+}
+
+// Article 已发布的图文
+type Article struct {
+	Title              string `json:"title"`
+	Author             string `json:"author"`
+	Digest             string `json:"digest"`
+	Content            string `json:"content"`
+	ContentSourceURL   string `json:"content_source_url"`
+	ThumbMediaID       string `json:"thumb_media_id"`
+	ShowCoverPic       uint   `json:"show_cover_pic"`
+	NeedOpenComment    uint   `json:"need_open_comment"`
+	OnlyFansCanComment uint   `json:"only_fans_can_comment"`
+	URL                string `json:"url"`
+	IsDeleted          bool   `json:"is_deleted"` // 该图文是否被删除
+}
+
+// ArticleList 已发布的图文列表
+type ArticleList struct {
+	TotalCount int64             `json:"total_count"` // 成功发布的图文消息总数
+	ItemCount  int64             `json:"item_count"`  // 本次调用获取的图文消息的数量
+	Item       []ArticleListItem `json:"item"`
+}
+
+// ArticleListItem 已发布的图文列表中的单条记录
+type ArticleListItem struct {
+	ArticleID string `json:"article_id"`
+	Content   struct {
+		NewsItem   []*Article `json:"news_item"`
+		CreateTime int64      `json:"create_time"`
+		UpdateTime int64      `json:"update_time"`
+	} `json:"content"`
+	UpdateTime int64 `json:"update_time"`
+}
+
+// Publish 发布草稿，返回发布任务的id，发布结果通过 PUBLISHJOBFINISH 事件推送
+func (freePublish *FreePublish) Publish(mediaID string) (publishID int64, err error) {
+	req := map[string]string{
+		"media_id": mediaID,
+	}
+	var res struct {
+		util.CommonError
+		PublishID int64 `json:"publish_id,string"`
+	}
+	err = freePublish.post(publishURL, req, &res, "Publish")
+	return res.PublishID, err
+}
+
+// SelectStatus 发布状态轮询
+func (freePublish *FreePublish) SelectStatus(publishID int64) (list PublishStatusList, err error) {
+	req := map[string]string{
+		"publish_id": fmt.Sprint(publishID),
+	}
+	var res struct {
+		util.CommonError
+		PublishStatusList
+	}
+	err = freePublish.post(selectStateURL, req, &res, "SelectStatus")
+	return res.PublishStatusList, err
+}
+
+// Delete 删除发布，index 为要删除的文章在图文消息中的位置，第一篇编号为1，不填或为0时删除全部文章
+func (freePublish *FreePublish) Delete(articleID string, index uint) error {
+	req := map[string]interface{}{
+		"article_id": articleID,
+		"index":      index,
+	}
+	var res struct{ util.CommonError }
+	return freePublish.post(deleteURL, req, &res, "Delete")
+}
+
+// GetArticle 通过 article_id 获取已发布文章
+func (freePublish *FreePublish) GetArticle(articleID string) ([]*Article, error) {
+	req := map[string]string{
+		"article_id": articleID,
+	}
+	var res struct {
+		util.CommonError
+		NewsItem []*Article `json:"news_item"`
+	}
+	if err := freePublish.post(getArticleURL, req, &res, "GetArticle"); err != nil {
+		return nil, err
+	}
+	return res.NewsItem, nil
+}
+
+// Paginate 获取成功发布列表，offset 从0开始，count 取值在1到20之间，noContent 为true时不返回content字段
+func (freePublish *FreePublish) Paginate(offset, count int64, noContent bool) (list ArticleList, err error) {
+	req := map[string]interface{}{
+		"offset":     offset,
+		"count":      count,
+		"no_content": 0,
+	}
+	if noContent {
+		req["no_content"] = 1
+	}
+	var res struct {
+		util.CommonError
+		ArticleList
+	}
+	err = freePublish.post(paginateURL, req, &res, "Paginate")
+	return res.ArticleList, err
+}
+
+func (freePublish *FreePublish) post(apiURL string, req, res interface{}, apiName string) error {
+	accessToken, err := freePublish.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	response, err := util.PostJSON(fmt.Sprintf("%s?access_token=%s", apiURL, accessToken), req)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithError(response, res, apiName)
+}
@@ -0,0 +1,97 @@
+package freepublish
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func newTestFreePublish() *FreePublish {
+	return NewFreePublish(&context.Context{AccessTokenHandle: mockAccessToken{}})
+}
+
+// matchBody 校验请求体的JSON内容
+func matchBody(t *testing.T, expected string) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		var got, want interface{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal([]byte(expected), &want); err != nil {
+			return false, err
+		}
+		return assert.Equal(t, want, got), nil
+	}
+}
+
+func TestPublishAndSelectStatus(t *testing.T) {
+	defer gock.Off()
+	gock.New(publishURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"media_id":"media1"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "publish_id": "100000001"})
+	gock.New(selectStateURL).AddMatcher(matchBody(t, `{"publish_id":"100000001"}`)).
+		Reply(200).JSON(map[string]interface{}{
+		"publish_id":     "100000001",
+		"publish_status": 2,
+		"fail_idx":       []int{1, 2},
+	})
+
+	freePublish := newTestFreePublish()
+	publishID, err := freePublish.Publish("media1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100000001), publishID)
+
+	status, err := freePublish.SelectStatus(publishID)
+	assert.Nil(t, err)
+	assert.Equal(t, PublishStatusOriginalFail, status.PublishStatus)
+	assert.Equal(t, []uint{1, 2}, status.FailIdx)
+	assert.True(t, gock.IsDone())
+}
+
+func TestGetArticleAndDelete(t *testing.T) {
+	defer gock.Off()
+	gock.New(getArticleURL).AddMatcher(matchBody(t, `{"article_id":"article1"}`)).
+		Reply(200).JSON(map[string]interface{}{"news_item": []map[string]interface{}{{"title": "t", "is_deleted": true}}})
+	gock.New(deleteURL).AddMatcher(matchBody(t, `{"article_id":"article1","index":0}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	freePublish := newTestFreePublish()
+	articles, err := freePublish.GetArticle("article1")
+	assert.Nil(t, err)
+	assert.Len(t, articles, 1)
+	assert.True(t, articles[0].IsDeleted)
+
+	assert.Nil(t, freePublish.Delete("article1", 0))
+	assert.True(t, gock.IsDone())
+}
+
+func TestPaginate(t *testing.T) {
+	defer gock.Off()
+	gock.New(paginateURL).AddMatcher(matchBody(t, `{"offset":20,"count":10,"no_content":0}`)).
+		Reply(200).JSON(map[string]interface{}{
+		"total_count": 21,
+		"item_count":  1,
+		"item":        []map[string]interface{}{{"article_id": "article1", "update_time": 1600000000}},
+	})
+	gock.New(paginateURL).Reply(200).JSON(map[string]interface{}{"errcode": 40001, "errmsg": "invalid credential"})
+
+	freePublish := newTestFreePublish()
+	list, err := freePublish.Paginate(20, 10, false)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(21), list.TotalCount)
+	assert.Equal(t, "article1", list.Item[0].ArticleID)
+
+	_, err = freePublish.Paginate(0, 10, false)
+	assert.NotNil(t, err)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,56 @@
+package invoice
+
+import (
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+)
+
+// AuthorizeEvent 用户授权开票事件
+type AuthorizeEvent struct {
+	OpenID         string
+	SuccOrderID    string // 授权成功的订单号，与 FailOrderID 二选一
+	FailOrderID    string // 授权失败的订单号
+	AuthorizeAppID string // 获取授权页链接的appid
+	Source         string // 授权来源，web 公众号开票，app app开票，wxa 小程序开票，wap h5开票
+	CreateTime     int64
+}
+
+// Succeeded 用户是否授权成功
+func (e *AuthorizeEvent) Succeeded() bool {
+	return e.SuccOrderID != ""
+}
+
+// OrderID 授权的订单号
+func (e *AuthorizeEvent) OrderID() string {
+	if e.SuccOrderID != "" {
+		return e.SuccOrderID
+	}
+	return e.FailOrderID
+}
+
+// ParseAuthorizeEvent 解析用户授权开票事件，不是该事件时返回false
+func ParseAuthorizeEvent(msg message.MixMessage) (*AuthorizeEvent, bool) {
+	if msg.MsgType != message.MsgTypeEvent || msg.Event != message.EventUserAuthorizeInvoice {
+		return nil, false
+	}
+	return &AuthorizeEvent{
+		OpenID:         string(msg.FromUserName),
+		SuccOrderID:    msg.SuccOrderID,
+		FailOrderID:    msg.FailOrderID,
+		AuthorizeAppID: msg.AuthorizeAppID,
+		Source:         msg.InvoiceSource,
+		CreateTime:     msg.CreateTime,
+	}, true
+}
+
+// AuthorizeHandler 包装消息处理方法，用户授权开票事件交给onAuthorize，其余消息交给next，可直接传给 server.SetMessageHandler
+func AuthorizeHandler(onAuthorize func(e *AuthorizeEvent) *message.Reply, next func(msg message.MixMessage) *message.Reply) func(msg message.MixMessage) *message.Reply {
+	return func(msg message.MixMessage) *message.Reply {
+		if e, ok := ParseAuthorizeEvent(msg); ok {
+			return onAuthorize(e)
+		}
+		if next == nil {
+			return nil
+		}
+		return next(msg)
+	}
+}
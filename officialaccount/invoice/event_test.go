@@ -0,0 +1,37 @@
+package invoice
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAuthorizeEvent(t *testing.T) {
+	raw := `<xml>
+<ToUserName><![CDATA[gh_fc0a06a20993]]></ToUserName>
+<FromUserName><![CDATA[oZI8Fj040-be6rlDohc6gkoPOQTQ]]></FromUserName>
+<CreateTime>1475134700</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[user_authorize_invoice]]></Event>
+<SuccOrderId><![CDATA[1202933957956]]></SuccOrderId>
+<FailOrderId><![CDATA[]]></FailOrderId>
+<AuthorizeAppId><![CDATA[wxf8b4f85f3a794e77]]></AuthorizeAppId>
+<Source><![CDATA[web]]></Source>
+</xml>`
+	var msg message.MixMessage
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &msg))
+
+	e, ok := ParseAuthorizeEvent(msg)
+	assert.True(t, ok)
+	assert.True(t, e.Succeeded())
+	assert.Equal(t, "1202933957956", e.OrderID())
+	assert.Equal(t, "wxf8b4f85f3a794e77", e.AuthorizeAppID)
+	assert.Equal(t, "web", e.Source)
+	assert.Equal(t, "oZI8Fj040-be6rlDohc6gkoPOQTQ", e.OpenID)
+
+	msg.Event = message.EventSubscribe
+	_, ok = ParseAuthorizeEvent(msg)
+	assert.False(t, ok)
+}
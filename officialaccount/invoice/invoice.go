@@ -0,0 +1,244 @@
+package invoice
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/officialaccount/js"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	getAuthURLURL   = "https://api.weixin.qq.com/card/invoice/getauthurl"
+	getAuthDataURL  = "https://api.weixin.qq.com/card/invoice/getauthdata"
+	rejectInsertURL = "https://api.weixin.qq.com/card/invoice/rejectinsert"
+	createCardURL   = "https://api.weixin.qq.com/card/invoice/platform/createcard"
+	insertURL       = "https://api.weixin.qq.com/card/invoice/insert"
+	setURLURL       = "https://api.weixin.qq.com/card/invoice/seturl"
+)
+
+// AuthType 授权页类型
+type AuthType int
+
+const (
+	// AuthTypeInvoice 开票授权
+	AuthTypeInvoice AuthType = iota
+	// AuthTypeFillField 填写字段开票授权
+	AuthTypeFillField
+	// AuthTypeReceive 领票授权
+	AuthTypeReceive
+)
+
+// Invoice 电子发票
+type Invoice struct {
+	*context.Context
+}
+
+// NewInvoice init
+func NewInvoice(ctx *context.Context) *Invoice {
+	return &Invoice{
+		Context: ctx,
+	}
+}
+
+// AuthURLRequest 获取授权页链接的参数
+type AuthURLRequest struct {
+	SPAppID     string   `json:"s_pappid"`  // 开票平台在微信的标识号
+	OrderID     string   `json:"order_id"`  // 订单id，在商户内单笔开票请求的唯一识别号
+	Money       int64    `json:"money"`     // 订单金额，以分为单位
+	Timestamp   int64    `json:"timestamp"` // 时间戳，为0时使用当前时间
+	Source      string   `json:"source"`    // 开票来源，app、web、wap、wxa
+	RedirectURL string   `json:"redirect_url,omitempty"`
+	Ticket      string   `json:"ticket"` // 卡券api_ticket，为空时自动获取
+	Type        AuthType `json:"type"`
+}
+
+// UserAuthInfo 用户授权的开票信息
+type UserAuthInfo struct {
+	UserField *struct {
+		Title       string `json:"title"`
+		Phone       string `json:"phone"`
+		Email       string `json:"email"`
+		CustomField []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"custom_field"`
+	} `json:"user_field,omitempty"` // 个人抬头
+	BizField *struct {
+		Title       string `json:"title"`
+		TaxNo       string `json:"tax_no"`
+		Addr        string `json:"addr"`
+		Phone       string `json:"phone"`
+		BankType    string `json:"bank_type"`
+		BankNo      string `json:"bank_no"`
+		CustomField []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"custom_field"`
+	} `json:"biz_field,omitempty"` // 单位抬头
+}
+
+// AuthData 授权完成状态
+type AuthData struct {
+	InvoiceStatus string       `json:"invoice_status"` // auth success 表示授权成功
+	AuthTime      int64        `json:"auth_time"`
+	UserAuthInfo  UserAuthInfo `json:"user_auth_info"`
+}
+
+// CardInfo 发票卡券模板
+type CardInfo struct {
+	Payee    string `json:"payee"` // 收款方（开票方）全称
+	Type     string `json:"type"`  // 发票类型
+	BaseInfo struct {
+		LogoURL           string `json:"logo_url"`
+		Title             string `json:"title"`
+		CustomURLName     string `json:"custom_url_name,omitempty"`
+		CustomURL         string `json:"custom_url,omitempty"`
+		CustomURLSubTitle string `json:"custom_url_sub_title,omitempty"`
+		PromotionURLName  string `json:"promotion_url_name,omitempty"`
+		PromotionURL      string `json:"promotion_url,omitempty"`
+	} `json:"base_info"`
+}
+
+// UserInvoice 插入卡包的发票信息
+type UserInvoice struct {
+	Fee                   int64  `json:"fee"`             // 发票的金额，以分为单位
+	Title                 string `json:"title"`           // 发票的抬头
+	BillingTime           int64  `json:"billing_time"`    // 发票的开票时间
+	BillingNo             string `json:"billing_no"`      // 发票代码
+	BillingCode           string `json:"billing_code"`    // 发票号码
+	Info                  []Item `json:"info,omitempty"`  // 商品信息
+	FeeWithoutTax         int64  `json:"fee_without_tax"` // 不含税金额，以分为单位
+	Tax                   int64  `json:"tax"`             // 税额，以分为单位
+	SPdfMediaID           string `json:"s_pdf_media_id"`  // 发票pdf文件上传后得到的s_media_id
+	STripPdfMediaID       string `json:"s_trip_pdf_media_id,omitempty"`
+	CheckCode             string `json:"check_code"` // 校验码
+	BuyerNumber           string `json:"buyer_number,omitempty"`
+	BuyerAddressAndPhone  string `json:"buyer_address_and_phone,omitempty"`
+	BuyerBankAccount      string `json:"buyer_bank_account,omitempty"`
+	SellerNumber          string `json:"seller_number,omitempty"`
+	SellerAddressAndPhone string `json:"seller_address_and_phone,omitempty"`
+	SellerBankAccount     string `json:"seller_bank_account,omitempty"`
+	Remarks               string `json:"remarks,omitempty"`
+	Cashier               string `json:"cashier,omitempty"`
+	Maker                 string `json:"maker,omitempty"`
+}
+
+// Item 发票中的商品信息
+type Item struct {
+	Name  string `json:"name"`
+	Num   int64  `json:"num,omitempty"`
+	Unit  string `json:"unit,omitempty"`
+	Price int64  `json:"price"` // 单价，以分为单位
+}
+
+// InsertResult 插入卡包的结果
+type InsertResult struct {
+	Code    string `json:"code"`
+	OpenID  string `json:"openid"`
+	UnionID string `json:"unionid"`
+}
+
+// GetAuthURL 获取授权页链接，返回授权页链接与开票平台的appid
+// 自动填充的ticket与时间戳只作用于本次请求，不会写回 req
+func (invoice *Invoice) GetAuthURL(req *AuthURLRequest) (authURL, appID string, err error) {
+	filled := *req
+	if filled.Ticket == "" {
+		if filled.Ticket, err = js.NewJs(invoice.Context).GetCardTicket(); err != nil {
+			return
+		}
+	}
+	if filled.Timestamp == 0 {
+		filled.Timestamp = util.GetCurrTS()
+	}
+	var res struct {
+		util.CommonError
+		AuthURL string `json:"auth_url"`
+		AppID   string `json:"appid"`
+	}
+	err = invoice.post(getAuthURLURL, &filled, &res, "GetAuthURL")
+	return res.AuthURL, res.AppID, err
+}
+
+// GetAuthData 查询授权完成状态
+func (invoice *Invoice) GetAuthData(sPAppID, orderID string) (*AuthData, error) {
+	req := map[string]string{
+		"s_pappid": sPAppID,
+		"order_id": orderID,
+	}
+	var res struct {
+		util.CommonError
+		AuthData
+	}
+	if err := invoice.post(getAuthDataURL, req, &res, "GetAuthData"); err != nil {
+		return nil, err
+	}
+	return &res.AuthData, nil
+}
+
+// RejectInsert 拒绝开票，reason 会展示给用户，url 为引导用户重新开票的链接
+func (invoice *Invoice) RejectInsert(sPAppID, orderID, reason, url string) error {
+	req := map[string]string{
+		"s_pappid": sPAppID,
+		"order_id": orderID,
+		"reason":   reason,
+		"url":      url,
+	}
+	var res struct{ util.CommonError }
+	return invoice.post(rejectInsertURL, req, &res, "RejectInsert")
+}
+
+// CreateCard 创建发票卡券模板，返回card_id
+func (invoice *Invoice) CreateCard(info *CardInfo) (cardID string, err error) {
+	var res struct {
+		util.CommonError
+		CardID string `json:"card_id"`
+	}
+	err = invoice.post(createCardURL, map[string]*CardInfo{"invoice_info": info}, &res, "CreateInvoiceCard")
+	return res.CardID, err
+}
+
+// Insert 将电子发票插入用户卡包，appID 为用户开票授权时的appid
+func (invoice *Invoice) Insert(orderID, cardID, appID string, userInvoice *UserInvoice) (*InsertResult, error) {
+	req := map[string]interface{}{
+		"order_id": orderID,
+		"card_id":  cardID,
+		"appid":    appID,
+		"card_ext": map[string]interface{}{
+			"nonce_str": util.RandomStr(16),
+			"user_card": map[string]interface{}{
+				"invoice_user_data": userInvoice,
+			},
+		},
+	}
+	var res struct {
+		util.CommonError
+		InsertResult
+	}
+	if err := invoice.post(insertURL, req, &res, "InsertInvoice"); err != nil {
+		return nil, err
+	}
+	return &res.InsertResult, nil
+}
+
+// SetURL 获取开票平台的授权链接，开票平台需要将该链接提供给商户完成授权
+func (invoice *Invoice) SetURL() (string, error) {
+	var res struct {
+		util.CommonError
+		InvoiceURL string `json:"invoice_url"`
+	}
+	err := invoice.post(setURLURL, map[string]string{}, &res, "SetInvoiceURL")
+	return res.InvoiceURL, err
+}
+
+func (invoice *Invoice) post(apiURL string, req, res interface{}, apiName string) error {
+	accessToken, err := invoice.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	response, err := util.PostJSON(fmt.Sprintf("%s?access_token=%s", apiURL, accessToken), req)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithError(response, res, apiName)
+}
@@ -0,0 +1,50 @@
+package invoice
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/officialaccount/config"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func TestGetAuthURLDoesNotModifyRequest(t *testing.T) {
+	defer gock.Off()
+	//卡券api_ticket 获取后被缓存，只请求一次
+	gock.New("https://api.weixin.qq.com").Get("/cgi-bin/ticket/getticket").MatchParam("type", "wx_card").
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "ticket": "card-ticket", "expires_in": 7200})
+	gock.New(getAuthURLURL).MatchParam("access_token", "mock-ak").Times(2).
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			var body AuthURLRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return false, err
+			}
+			return body.Ticket == "card-ticket" && body.Timestamp > 0 && body.OrderID == "order1", nil
+		}).
+		Reply(200).JSON(map[string]interface{}{"auth_url": "https://mp.weixin.qq.com/bizmall/authinvoice", "appid": "wx-platform"})
+
+	invoice := NewInvoice(&context.Context{
+		Config:            &config.Config{AppID: "wx123", Cache: cache.NewMemory()},
+		AccessTokenHandle: mockAccessToken{},
+	})
+	req := &AuthURLRequest{SPAppID: "sp", OrderID: "order1", Money: 100, Source: "web", Type: AuthTypeInvoice}
+	for i := 0; i < 2; i++ {
+		authURL, appID, err := invoice.GetAuthURL(req)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://mp.weixin.qq.com/bizmall/authinvoice", authURL)
+		assert.Equal(t, "wx-platform", appID)
+		assert.Equal(t, "", req.Ticket)
+		assert.Equal(t, int64(0), req.Timestamp)
+	}
+	assert.True(t, gock.IsDone())
+}
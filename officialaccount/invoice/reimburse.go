@@ -0,0 +1,111 @@
+package invoice
+
+import (
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	getInvoiceInfoURL      = "https://api.weixin.qq.com/card/invoice/reimburse/getinvoiceinfo"
+	getInvoiceBatchURL     = "https://api.weixin.qq.com/card/invoice/reimburse/getinvoicebatch"
+	updateInvoiceStatusURL = "https://api.weixin.qq.com/card/invoice/reimburse/updateinvoicestatus"
+	updateStatusBatchURL   = "https://api.weixin.qq.com/card/invoice/reimburse/updatestatusbatch"
+)
+
+// ReimburseStatus 发票报销状态
+type ReimburseStatus string
+
+const (
+	// ReimburseStatusInit 发票初始状态，未锁定
+	ReimburseStatusInit ReimburseStatus = "INVOICE_REIMBURSE_INIT"
+	// ReimburseStatusLock 发票已锁定
+	ReimburseStatusLock ReimburseStatus = "INVOICE_REIMBURSE_LOCK"
+	// ReimburseStatusClosure 发票已核销，从用户卡包中移除
+	ReimburseStatusClosure ReimburseStatus = "INVOICE_REIMBURSE_CLOSURE"
+)
+
+// CardItem 定位一张发票
+type CardItem struct {
+	CardID      string `json:"card_id"`
+	EncryptCode string `json:"encrypt_code"`
+}
+
+// Info 发票详情
+type Info struct {
+	CardID    string `json:"card_id"`
+	BeginTime int64  `json:"begin_time"`
+	EndTime   int64  `json:"end_time"`
+	OpenID    string `json:"openid"`
+	Type      string `json:"type"`
+	Payee     string `json:"payee"`
+	Detail    string `json:"detail"`
+	UserInfo  struct {
+		Fee                   int64           `json:"fee"`
+		Title                 string          `json:"title"`
+		BillingTime           int64           `json:"billing_time"`
+		BillingNo             string          `json:"billing_no"`
+		BillingCode           string          `json:"billing_code"`
+		Info                  []Item          `json:"info"`
+		FeeWithoutTax         int64           `json:"fee_without_tax"`
+		Tax                   int64           `json:"tax"`
+		Detail                string          `json:"detail"`
+		PdfURL                string          `json:"pdf_url"`
+		TripPdfURL            string          `json:"trip_pdf_url"`
+		ReimburseStatus       ReimburseStatus `json:"reimburse_status"`
+		CheckCode             string          `json:"check_code"`
+		BuyerNumber           string          `json:"buyer_number"`
+		BuyerAddressAndPhone  string          `json:"buyer_address_and_phone"`
+		BuyerBankAccount      string          `json:"buyer_bank_account"`
+		SellerNumber          string          `json:"seller_number"`
+		SellerAddressAndPhone string          `json:"seller_address_and_phone"`
+		SellerBankAccount     string          `json:"seller_bank_account"`
+		Remarks               string          `json:"remarks"`
+		Cashier               string          `json:"cashier"`
+		Maker                 string          `json:"maker"`
+	} `json:"user_info"`
+}
+
+// GetInvoiceInfo 查询报销发票信息
+func (invoice *Invoice) GetInvoiceInfo(cardID, encryptCode string) (*Info, error) {
+	var res struct {
+		util.CommonError
+		Info
+	}
+	if err := invoice.post(getInvoiceInfoURL, &CardItem{cardID, encryptCode}, &res, "GetInvoiceInfo"); err != nil {
+		return nil, err
+	}
+	return &res.Info, nil
+}
+
+// GetInvoiceBatch 批量查询报销发票信息
+func (invoice *Invoice) GetInvoiceBatch(items []CardItem) ([]*Info, error) {
+	var res struct {
+		util.CommonError
+		ItemList []*Info `json:"item_list"`
+	}
+	if err := invoice.post(getInvoiceBatchURL, map[string][]CardItem{"item_list": items}, &res, "GetInvoiceBatch"); err != nil {
+		return nil, err
+	}
+	return res.ItemList, nil
+}
+
+// UpdateInvoiceStatus 更新单张发票的报销状态
+func (invoice *Invoice) UpdateInvoiceStatus(cardID, encryptCode string, status ReimburseStatus) error {
+	req := map[string]interface{}{
+		"card_id":          cardID,
+		"encrypt_code":     encryptCode,
+		"reimburse_status": status,
+	}
+	var res struct{ util.CommonError }
+	return invoice.post(updateInvoiceStatusURL, req, &res, "UpdateInvoiceStatus")
+}
+
+// UpdateStatusBatch 批量更新同一用户的发票报销状态
+func (invoice *Invoice) UpdateStatusBatch(openID string, status ReimburseStatus, items []CardItem) error {
+	req := map[string]interface{}{
+		"openid":           openID,
+		"reimburse_status": status,
+		"invoice_list":     items,
+	}
+	var res struct{ util.CommonError }
+	return invoice.post(updateStatusBatchURL, req, &res, "UpdateStatusBatch")
+}
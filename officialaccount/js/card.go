@@ -0,0 +1,91 @@
+package js
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+// signTypeSHA1 卡券签名方式
+const signTypeSHA1 = "SHA1"
+
+// ChooseCardConfig wx.chooseCard 的参数
+type ChooseCardConfig struct {
+	ShopID    string `json:"shopId"`
+	CardType  string `json:"cardType"`
+	CardID    string `json:"cardId"`
+	Timestamp int64  `json:"timestamp"`
+	NonceStr  string `json:"nonceStr"`
+	SignType  string `json:"signType"`
+	CardSign  string `json:"cardSign"`
+}
+
+// CardExt wx.addCard 中单张卡券的附加信息
+type CardExt struct {
+	Code                string `json:"code,omitempty"`
+	OpenID              string `json:"openid,omitempty"`
+	Timestamp           string `json:"timestamp"`
+	NonceStr            string `json:"nonce_str"`
+	FixedBeginTimestamp int64  `json:"fixed_begintimestamp,omitempty"`
+	OuterStr            string `json:"outer_str,omitempty"`
+	Signature           string `json:"signature"`
+}
+
+// AddCardItem wx.addCard 中 cardList 的单个元素
+type AddCardItem struct {
+	CardID  string `json:"cardId"`
+	CardExt string `json:"cardExt"`
+}
+
+// CardSignature 卡券签名：将api_ticket与参与签名的参数值按字典序排序后拼接做sha1
+func CardSignature(apiTicket string, values ...string) string {
+	return util.Signature(append([]string{apiTicket}, values...)...)
+}
+
+// GetChooseCardConfig 获取 wx.chooseCard 需要的参数，shopID、cardType、cardID 均可为空
+func (js *Js) GetChooseCardConfig(shopID, cardType, cardID string) (*ChooseCardConfig, error) {
+	ticket, err := js.GetCardTicket()
+	if err != nil {
+		return nil, err
+	}
+	config := &ChooseCardConfig{
+		ShopID:    shopID,
+		CardType:  cardType,
+		CardID:    cardID,
+		Timestamp: util.GetCurrTS(),
+		NonceStr:  util.RandomStr(16),
+		SignType:  signTypeSHA1,
+	}
+	config.CardSign = CardSignature(ticket, js.AppID, shopID, strconv.FormatInt(config.Timestamp, 10), config.NonceStr, cardID, cardType)
+	return config, nil
+}
+
+// GetCardExt 获取 wx.addCard 的cardExt，code 与 openID 仅在卡券为自定义code或指定用户领取时填写
+func (js *Js) GetCardExt(cardID, code, openID string) (*CardExt, error) {
+	ticket, err := js.GetCardTicket()
+	if err != nil {
+		return nil, err
+	}
+	ext := &CardExt{
+		Code:      code,
+		OpenID:    openID,
+		Timestamp: strconv.FormatInt(util.GetCurrTS(), 10),
+		NonceStr:  util.RandomStr(16),
+	}
+	ext.Signature = CardSignature(ticket, ext.Timestamp, cardID, code, openID, ext.NonceStr)
+	return ext, nil
+}
+
+// GetAddCardItem 获取 wx.addCard 的 cardList 元素，cardExt 已编码为JSON字符串
+func (js *Js) GetAddCardItem(cardID, code, openID string) (*AddCardItem, error) {
+	ext, err := js.GetCardExt(cardID, code, openID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(ext)
+	if err != nil {
+		return nil, err
+	}
+	return &AddCardItem{CardID: cardID, CardExt: string(data)}, nil
+}
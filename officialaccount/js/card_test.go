@@ -0,0 +1,44 @@
+package js
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/config"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTicket string
+
+func (t stubTicket) GetTicket(string) (string, error) {
+	return string(t), nil
+}
+
+type stubAccessToken struct{}
+
+func (stubAccessToken) GetAccessToken() (string, error) {
+	return "ak", nil
+}
+
+func TestCardSignature(t *testing.T) {
+	expected := fmt.Sprintf("%x", sha1.Sum([]byte("1404896688"+"7T8Hd"+"card1"+"ticket")))
+	assert.Equal(t, expected, CardSignature("ticket", "card1", "1404896688", "7T8Hd"))
+	assert.Equal(t, expected, CardSignature("ticket", "7T8Hd", "", "1404896688", "card1"))
+}
+
+func TestGetAddCardItem(t *testing.T) {
+	js := NewJs(&context.Context{Config: &config.Config{AppID: "wx123"}, AccessTokenHandle: stubAccessToken{}})
+	js.SetCardTicketHandle(stubTicket("card-ticket"))
+
+	item, err := js.GetAddCardItem("card1", "", "openid1")
+	assert.Nil(t, err)
+	assert.Equal(t, "card1", item.CardID)
+
+	var ext CardExt
+	assert.Nil(t, json.Unmarshal([]byte(item.CardExt), &ext))
+	assert.Equal(t, "openid1", ext.OpenID)
+	assert.Equal(t, CardSignature("card-ticket", ext.Timestamp, "card1", "openid1", ext.NonceStr), ext.Signature)
+}
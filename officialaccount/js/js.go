@@ -12,14 +12,42 @@ import (
 type Js struct {
 	*context.Context
 	credential.JsTicketHandle
+	cardTicketHandle credential.JsTicketHandle
 }
 
 // Config 返回给用户jssdk配置信息
 type Config struct {
-	AppID     string `json:"app_id"`
-	Timestamp int64  `json:"timestamp"`
-	NonceStr  string `json:"nonce_str"`
-	Signature string `json:"signature"`
+	AppID     string   `json:"app_id"`
+	Timestamp int64    `json:"timestamp"`
+	NonceStr  string   `json:"nonce_str"`
+	Signature string   `json:"signature"`
+	JsAPIList []string `json:"js_api_list,omitempty"`
+}
+
+// WxConfig 可直接传给前端 wx.config 的配置
+type WxConfig struct {
+	Debug     bool     `json:"debug"`
+	AppID     string   `json:"appId"`
+	Timestamp int64    `json:"timestamp"`
+	NonceStr  string   `json:"nonceStr"`
+	Signature string   `json:"signature"`
+	JsAPIList []string `json:"jsApiList"`
+}
+
+// WxConfig 转换为 wx.config 的参数格式
+func (config *Config) WxConfig(debug bool) *WxConfig {
+	jsAPIList := config.JsAPIList
+	if jsAPIList == nil {
+		jsAPIList = []string{}
+	}
+	return &WxConfig{
+		Debug:     debug,
+		AppID:     config.AppID,
+		Timestamp: config.Timestamp,
+		NonceStr:  config.NonceStr,
+		Signature: config.Signature,
+		JsAPIList: jsAPIList,
+	}
 }
 
 //NewJs init
@@ -28,6 +56,8 @@ func NewJs(context *context.Context) *Js {
 	js.Context = context
 	jsTicketHandle := credential.NewDefaultJsTicket(context.AppID, credential.CacheKeyOfficialAccountPrefix, context.Cache)
 	js.SetJsTicketHandle(jsTicketHandle)
+	cardTicketHandle := credential.NewDefaultTicket(context.AppID, credential.CacheKeyOfficialAccountPrefix, context.Cache, credential.TicketTypeWxCard)
+	js.SetCardTicketHandle(cardTicketHandle)
 	return js
 }
 
@@ -36,9 +66,23 @@ func (js *Js) SetJsTicketHandle(ticketHandle credential.JsTicketHandle) {
 	js.JsTicketHandle = ticketHandle
 }
 
+//SetCardTicketHandle 自定义卡券api_ticket取值方式
+func (js *Js) SetCardTicketHandle(ticketHandle credential.JsTicketHandle) {
+	js.cardTicketHandle = ticketHandle
+}
+
+//GetCardTicket 获取卡券api_ticket
+func (js *Js) GetCardTicket() (string, error) {
+	accessToken, err := js.GetAccessToken()
+	if err != nil {
+		return "", err
+	}
+	return js.cardTicketHandle.GetTicket(accessToken)
+}
+
 //GetConfig 获取jssdk需要的配置参数
-//uri 为当前网页地址
-func (js *Js) GetConfig(uri string) (config *Config, err error) {
+//uri 为当前网页地址，jsAPIList 为需要使用的JS接口列表
+func (js *Js) GetConfig(uri string, jsAPIList ...string) (config *Config, err error) {
 	config = new(Config)
 	var accessToken string
 	accessToken, err = js.GetAccessToken()
@@ -60,5 +104,6 @@ func (js *Js) GetConfig(uri string) (config *Config, err error) {
 	config.NonceStr = nonceStr
 	config.Timestamp = timestamp
 	config.Signature = sigStr
+	config.JsAPIList = jsAPIList
 	return
 }
@@ -0,0 +1,64 @@
+package message
+
+import (
+	"errors"
+	"sync"
+)
+
+//BulkSendResult 批量发送客服消息时单个用户的发送结果
+type BulkSendResult struct {
+	OpenID string
+	Err    error
+	//ErrCode 微信返回的错误码，非微信返回的错误时为0
+	ErrCode int64
+	//WindowExpired 用户超过48小时未与公众号互动(45015)
+	WindowExpired bool
+}
+
+//Success 是否发送成功
+func (res *BulkSendResult) Success() bool {
+	return res.Err == nil
+}
+
+//BulkSend 将同一条客服消息并发发送给多个用户，concurrency 为最大并发数
+//返回结果与 openIDs 顺序一致
+func (manager *Manager) BulkSend(openIDs []string, msg *CustomerMessage, concurrency int) []*BulkSendResult {
+	return manager.BulkSendFunc(openIDs, func(openID string) *CustomerMessage {
+		userMsg := *msg
+		userMsg.ToUser = openID
+		return &userMsg
+	}, concurrency)
+}
+
+//BulkSendFunc 为每个用户构造客服消息并并发发送，concurrency 为最大并发数
+func (manager *Manager) BulkSendFunc(openIDs []string, build func(openID string) *CustomerMessage, concurrency int) []*BulkSendResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	results := make([]*BulkSendResult, len(openIDs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, openID := range openIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, openID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = newBulkSendResult(openID, manager.Send(build(openID)))
+		}(i, openID)
+	}
+	wg.Wait()
+	return results
+}
+
+func newBulkSendResult(openID string, err error) *BulkSendResult {
+	res := &BulkSendResult{OpenID: openID, Err: err}
+	var sendErr *CustomerSendError
+	if errors.As(err, &sendErr) {
+		res.ErrCode = sendErr.ErrCode
+		res.WindowExpired = sendErr.ErrCode == ErrCodeResponseOutOfTime
+	}
+	return res
+}
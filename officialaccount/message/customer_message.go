@@ -2,6 +2,7 @@ package message
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/silenceper/wechat/v2/officialaccount/context"
@@ -12,15 +13,32 @@ const (
 	customerSendMessage = "https://api.weixin.qq.com/cgi-bin/message/custom/send"
 )
 
+//ErrCodeResponseOutOfTime 用户超过48小时未与公众号互动，无法再下发客服消息
+const ErrCodeResponseOutOfTime = 45015
+
+//CustomerSendError 发送客服消息时微信返回的错误
+type CustomerSendError struct {
+	ErrCode int64
+	ErrMsg  string
+}
+
+//Error 实现error接口
+func (e *CustomerSendError) Error() string {
+	return fmt.Sprintf("customer msg send error : errcode=%v , errmsg=%v", e.ErrCode, e.ErrMsg)
+}
+
 //Manager 消息管理者，可以发送消息
 type Manager struct {
 	*context.Context
+
+	//SkipValidate 为true时 Send 不在本地校验消息内容，完全交由微信判断
+	SkipValidate bool
 }
 
 //NewMessageManager 实例化消息管理者
 func NewMessageManager(context *context.Context) *Manager {
 	return &Manager{
-		context,
+		Context: context,
 	}
 }
 
@@ -38,6 +56,23 @@ type CustomerMessage struct {
 	Wxcard          *MediaWxcard          `json:"wxcard,omitempty"`          //可选
 	Msgmenu         *MediaMsgmenu         `json:"msgmenu,omitempty"`         //可选
 	Miniprogrampage *MediaMiniprogrampage `json:"miniprogrampage,omitempty"` //可选
+	Mpnewsarticle   *MediaMpnewsarticle   `json:"mpnewsarticle,omitempty"`   //可选
+	CustomService   *CustomService        `json:"customservice,omitempty"`   //可选，以某个客服帐号来发消息
+}
+
+//CustomService 指定发送消息的客服帐号
+type CustomService struct {
+	KfAccount string `json:"kf_account"`
+}
+
+//WithKfAccount 以指定客服帐号发送消息，kfAccount 格式为：帐号前缀@公众号微信号
+func (msg *CustomerMessage) WithKfAccount(kfAccount string) *CustomerMessage {
+	if kfAccount == "" {
+		msg.CustomService = nil
+		return msg
+	}
+	msg.CustomService = &CustomService{KfAccount: kfAccount}
+	return msg
 }
 
 //NewCustomerTextMessage 文本消息结构体构造方法
@@ -73,6 +108,106 @@ func NewCustomerVoiceMessage(toUser, mediaID string) *CustomerMessage {
 	}
 }
 
+//NewCustomerVideoMessage 视频消息的构造方法
+func NewCustomerVideoMessage(toUser, mediaID, thumbMediaID, title, description string) *CustomerMessage {
+	return &CustomerMessage{
+		ToUser:  toUser,
+		Msgtype: MsgTypeVideo,
+		Video: &MediaVideo{
+			MediaID:      mediaID,
+			ThumbMediaID: thumbMediaID,
+			Title:        title,
+			Description:  description,
+		},
+	}
+}
+
+//NewCustomerMusicMessage 音乐消息的构造方法
+func NewCustomerMusicMessage(toUser, title, description, musicURL, hqMusicURL, thumbMediaID string) *CustomerMessage {
+	return &CustomerMessage{
+		ToUser:  toUser,
+		Msgtype: MsgTypeMusic,
+		Music: &MediaMusic{
+			Title:        title,
+			Description:  description,
+			Musicurl:     musicURL,
+			Hqmusicurl:   hqMusicURL,
+			ThumbMediaID: thumbMediaID,
+		},
+	}
+}
+
+//NewCustomerNewsMessage 图文消息（点击跳转到外链）的构造方法，图文消息条数限制在1条以内
+func NewCustomerNewsMessage(toUser string, articles ...MediaArticles) *CustomerMessage {
+	return &CustomerMessage{
+		ToUser:  toUser,
+		Msgtype: MsgTypeNews,
+		News: &MediaNews{
+			Articles: articles,
+		},
+	}
+}
+
+//NewCustomerMpnewsMessage 图文消息（点击跳转到图文消息页面）的构造方法
+func NewCustomerMpnewsMessage(toUser, mediaID string) *CustomerMessage {
+	return &CustomerMessage{
+		ToUser:  toUser,
+		Msgtype: MsgTypeMpNews,
+		Mpnews: &MediaResource{
+			mediaID,
+		},
+	}
+}
+
+//NewCustomerMpnewsArticleMessage 已发布图文消息的构造方法，articleID 为发布后获得的article_id
+func NewCustomerMpnewsArticleMessage(toUser, articleID string) *CustomerMessage {
+	return &CustomerMessage{
+		ToUser:  toUser,
+		Msgtype: MsgTypeMpNewsArticle,
+		Mpnewsarticle: &MediaMpnewsarticle{
+			articleID,
+		},
+	}
+}
+
+//NewCustomerMsgmenuMessage 菜单消息的构造方法
+func NewCustomerMsgmenuMessage(toUser, headContent, tailContent string, list []MsgmenuItem) *CustomerMessage {
+	return &CustomerMessage{
+		ToUser:  toUser,
+		Msgtype: MsgTypeMsgMenu,
+		Msgmenu: &MediaMsgmenu{
+			HeadContent: headContent,
+			List:        list,
+			TailContent: tailContent,
+		},
+	}
+}
+
+//NewCustomerWxcardMessage 卡券消息的构造方法
+func NewCustomerWxcardMessage(toUser, cardID string) *CustomerMessage {
+	return &CustomerMessage{
+		ToUser:  toUser,
+		Msgtype: MsgTypeWxCard,
+		Wxcard: &MediaWxcard{
+			cardID,
+		},
+	}
+}
+
+//NewCustomerMiniprogrampageMessage 小程序卡片消息的构造方法
+func NewCustomerMiniprogrampageMessage(toUser, title, appID, pagePath, thumbMediaID string) *CustomerMessage {
+	return &CustomerMessage{
+		ToUser:  toUser,
+		Msgtype: MsgTypeMiniProgramPage,
+		Miniprogrampage: &MediaMiniprogrampage{
+			Title:        title,
+			Appid:        appID,
+			Pagepath:     pagePath,
+			ThumbMediaID: thumbMediaID,
+		},
+	}
+}
+
 //MediaText 文本消息的文字
 type MediaText struct {
 	Content string `json:"content"`
@@ -91,6 +226,11 @@ type MediaVideo struct {
 	Description  string `json:"description"`
 }
 
+//MediaMpnewsarticle 已发布的图文消息
+type MediaMpnewsarticle struct {
+	ArticleID string `json:"article_id"`
+}
+
 //MediaMusic 音乐消息包括的内容
 type MediaMusic struct {
 	Title        string `json:"title"`
@@ -139,8 +279,54 @@ type MediaMiniprogrampage struct {
 	ThumbMediaID string `json:"thumb_media_id"`
 }
 
-//Send 发送客服消息
+//Validate 校验客服消息内容是否完整，未知的消息类型不做校验，交由微信判断
+func (msg *CustomerMessage) Validate() error {
+	if msg.ToUser == "" {
+		return errors.New("customer msg touser is empty")
+	}
+	if msg.CustomService != nil && msg.CustomService.KfAccount == "" {
+		return errors.New("customer msg kf_account is empty")
+	}
+	var valid bool
+	switch msg.Msgtype {
+	case MsgTypeText:
+		valid = msg.Text != nil && msg.Text.Content != ""
+	case MsgTypeImage:
+		valid = msg.Image != nil && msg.Image.MediaID != ""
+	case MsgTypeVoice:
+		valid = msg.Voice != nil && msg.Voice.MediaID != ""
+	case MsgTypeVideo:
+		valid = msg.Video != nil && msg.Video.MediaID != "" && msg.Video.ThumbMediaID != ""
+	case MsgTypeMusic:
+		valid = msg.Music != nil && msg.Music.Musicurl != "" && msg.Music.Hqmusicurl != "" && msg.Music.ThumbMediaID != ""
+	case MsgTypeNews:
+		valid = msg.News != nil && len(msg.News.Articles) == 1
+	case MsgTypeMpNews:
+		valid = msg.Mpnews != nil && msg.Mpnews.MediaID != ""
+	case MsgTypeMpNewsArticle:
+		valid = msg.Mpnewsarticle != nil && msg.Mpnewsarticle.ArticleID != ""
+	case MsgTypeMsgMenu:
+		valid = msg.Msgmenu != nil && len(msg.Msgmenu.List) > 0
+	case MsgTypeWxCard:
+		valid = msg.Wxcard != nil && msg.Wxcard.CardID != ""
+	case MsgTypeMiniProgramPage:
+		valid = msg.Miniprogrampage != nil && msg.Miniprogrampage.Appid != "" && msg.Miniprogrampage.Pagepath != "" && msg.Miniprogrampage.ThumbMediaID != ""
+	default:
+		return nil
+	}
+	if !valid {
+		return fmt.Errorf("customer msg content of type %s is invalid", msg.Msgtype)
+	}
+	return nil
+}
+
+//Send 发送客服消息，微信返回错误时 err 为 *CustomerSendError
 func (manager *Manager) Send(msg *CustomerMessage) error {
+	if !manager.SkipValidate {
+		if err := msg.Validate(); err != nil {
+			return err
+		}
+	}
 	accessToken, err := manager.Context.GetAccessToken()
 	if err != nil {
 		return err
@@ -156,8 +342,7 @@ func (manager *Manager) Send(msg *CustomerMessage) error {
 		return err
 	}
 	if result.ErrCode != 0 {
-		err = fmt.Errorf("customer msg send error : errcode=%v , errmsg=%v", result.ErrCode, result.ErrMsg)
-		return err
+		return &CustomerSendError{ErrCode: result.ErrCode, ErrMsg: result.ErrMsg}
 	}
 
 	return nil
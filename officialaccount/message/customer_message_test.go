@@ -0,0 +1,32 @@
+package message
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerMessageValidate(t *testing.T) {
+	assert.Nil(t, NewCustomerTextMessage("openid", "hello").WithKfAccount("test1@test").Validate())
+	assert.Nil(t, NewCustomerMpnewsArticleMessage("openid", "article-id").Validate())
+	assert.Nil(t, NewCustomerNewsMessage("openid", MediaArticles{Title: "title"}).Validate())
+	assert.NotNil(t, NewCustomerNewsMessage("openid").Validate())
+	assert.NotNil(t, NewCustomerImgMessage("", "media-id").Validate())
+	assert.NotNil(t, NewCustomerMiniprogrampageMessage("openid", "title", "appid", "", "thumb").Validate())
+	assert.Nil(t, (&CustomerMessage{ToUser: "openid", Msgtype: "unknown"}).Validate())
+	assert.NotNil(t, (&CustomerMessage{Msgtype: "unknown"}).Validate())
+}
+
+func TestNewBulkSendResult(t *testing.T) {
+	res := newBulkSendResult("openid", &CustomerSendError{ErrCode: ErrCodeResponseOutOfTime, ErrMsg: "response out of time limit"})
+	assert.False(t, res.Success())
+	assert.True(t, res.WindowExpired)
+	assert.Equal(t, int64(ErrCodeResponseOutOfTime), res.ErrCode)
+
+	res = newBulkSendResult("openid", errors.New("network error"))
+	assert.False(t, res.WindowExpired)
+	assert.Equal(t, int64(0), res.ErrCode)
+
+	assert.True(t, newBulkSendResult("openid", nil).Success())
+}
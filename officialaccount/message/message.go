@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 
 	"github.com/silenceper/wechat/v2/officialaccount/device"
+	"github.com/silenceper/wechat/v2/officialaccount/freepublish"
 )
 
 // MsgType 基本消息类型
@@ -38,6 +39,16 @@ const (
 	MsgTypeTransfer = "transfer_customer_service"
 	//MsgTypeEvent 表示事件推送消息
 	MsgTypeEvent = "event"
+	//MsgTypeMpNews 表示图文消息（点击跳转到图文消息页面）[限客服消息]
+	MsgTypeMpNews = "mpnews"
+	//MsgTypeMpNewsArticle 表示已发布的图文消息（点击跳转到图文消息页面）[限客服消息]
+	MsgTypeMpNewsArticle = "mpnewsarticle"
+	//MsgTypeMsgMenu 表示菜单消息[限客服消息]
+	MsgTypeMsgMenu = "msgmenu"
+	//MsgTypeWxCard 表示卡券消息[限客服消息]
+	MsgTypeWxCard = "wxcard"
+	//MsgTypeMiniProgramPage 表示小程序卡片消息[限客服消息]
+	MsgTypeMiniProgramPage = "miniprogrampage"
 )
 
 const (
@@ -69,6 +80,46 @@ const (
 	EventTemplateSendJobFinish = "TEMPLATESENDJOBFINISH"
 	//EventWxaMediaCheck 异步校验图片/音频是否含有违法违规内容推送事件
 	EventWxaMediaCheck = "wxa_media_check"
+	//EventKfCreateSession 客服接入会话
+	EventKfCreateSession = "kf_create_session"
+	//EventKfCloseSession 客服关闭会话
+	EventKfCloseSession = "kf_close_session"
+	//EventKfSwitchSession 客服转接会话
+	EventKfSwitchSession = "kf_switch_session"
+	//EventSubscribeMsgPopupEvent 用户操作订阅通知弹窗
+	EventSubscribeMsgPopupEvent = "subscribe_msg_popup_event"
+	//EventSubscribeMsgChangeEvent 用户管理订阅通知
+	EventSubscribeMsgChangeEvent = "subscribe_msg_change_event"
+	//EventSubscribeMsgSentEvent 发送订阅通知
+	EventSubscribeMsgSentEvent = "subscribe_msg_sent_event"
+	//EventCardPassCheck 卡券审核通过
+	EventCardPassCheck = "card_pass_check"
+	//EventCardNotPassCheck 卡券审核未通过
+	EventCardNotPassCheck = "card_not_pass_check"
+	//EventUserGetCard 用户领取卡券
+	EventUserGetCard = "user_get_card"
+	//EventUserGiftingCard 用户转赠卡券
+	EventUserGiftingCard = "user_gifting_card"
+	//EventUserDelCard 用户删除卡券
+	EventUserDelCard = "user_del_card"
+	//EventUserConsumeCard 卡券被核销
+	EventUserConsumeCard = "user_consume_card"
+	//EventUserViewCard 用户进入会员卡
+	EventUserViewCard = "user_view_card"
+	//EventUserEnterSessionFromCard 用户从卡券进入公众号会话
+	EventUserEnterSessionFromCard = "user_enter_session_from_card"
+	//EventCardSkuRemind 卡券库存报警
+	EventCardSkuRemind = "card_sku_remind"
+	//EventSubmitMemberCardUserInfo 用户通过一键激活提交信息
+	EventSubmitMemberCardUserInfo = "submit_membercard_user_info"
+	//EventUpdateMemberCard 会员卡积分余额变更
+	EventUpdateMemberCard = "update_member_card"
+	//EventUserAuthorizeInvoice 用户授权开票
+	EventUserAuthorizeInvoice = "user_authorize_invoice"
+	//EventMassSendJobFinish 群发任务完成
+	EventMassSendJobFinish = "MASSSENDJOBFINISH"
+	//EventPublishJobFinish 发布任务完成
+	EventPublishJobFinish = "PUBLISHJOBFINISH"
 )
 
 const (
@@ -153,6 +204,50 @@ type MixMessage struct {
 	OuterStr            string `xml:"OuterStr"`
 	IsRestoreMemberCard int32  `xml:"IsRestoreMemberCard"`
 	UnionID             string `xml:"UnionId"`
+	IsReturnBack        int32  `xml:"IsReturnBack"`
+	IsChatRoom          int32  `xml:"IsChatRoom"`
+	ConsumeSource       string `xml:"ConsumeSource"`
+	LocationName        string `xml:"LocationName"`
+	StaffOpenID         string `xml:"StaffOpenId"`
+	VerifyCode          string `xml:"VerifyCode"`
+	RemarkAmount        string `xml:"RemarkAmount"`
+	Detail              string `xml:"Detail"`
+	ModifyBonus         int64  `xml:"ModifyBonus"`
+	ModifyBalance       int64  `xml:"ModifyBalance"`
+
+	// 电子发票相关
+	SuccOrderID    string `xml:"SuccOrderId"`
+	FailOrderID    string `xml:"FailOrderId"`
+	AuthorizeAppID string `xml:"AuthorizeAppId"`
+	InvoiceSource  string `xml:"Source"` // 用户授权开票事件的开票来源
+
+	// 客服会话相关
+	KfAccount     string `xml:"KfAccount"`
+	FromKfAccount string `xml:"FromKfAccount"`
+	ToKfAccount   string `xml:"ToKfAccount"`
+	CloseType     string `xml:"CloseType"`
+
+	// 订阅通知相关
+	SubscribeMsgPopupEvent  []SubscribeMsgPopupEvent  `xml:"SubscribeMsgPopupEvent>List"`
+	SubscribeMsgChangeEvent []SubscribeMsgChangeEvent `xml:"SubscribeMsgChangeEvent>List"`
+	SubscribeMsgSentEvent   []SubscribeMsgSentEvent   `xml:"SubscribeMsgSentEvent>List"`
+
+	//群发消息相关，群发的消息ID为 TemplateMsgID，群发结果为 Status
+	TotalCount           int64                `xml:"TotalCount"`  //tag_id下粉丝数，或者openid_list中的粉丝数
+	FilterCount          int64                `xml:"FilterCount"` //过滤后准备发送的粉丝数
+	SentCount            int64                `xml:"SentCount"`   //发送成功的粉丝数
+	ErrorCount           int64                `xml:"ErrorCount"`  //发送失败的粉丝数
+	CopyrightCheckResult CopyrightCheckResult `xml:"CopyrightCheckResult"`
+	ArticleURLResult     struct {
+		Count      int `xml:"Count"`
+		ResultList []struct {
+			ArticleIdx int    `xml:"ArticleIdx"`
+			ArticleURL string `xml:"ArticleUrl"`
+		} `xml:"ResultList>item"`
+	} `xml:"ArticleUrlResult"`
+
+	//发布能力相关
+	PublishEventInfo PublishEventInfo `xml:"PublishEventInfo"`
 
 	// 内容审核相关
 	IsRisky       bool   `xml:"isrisky"`
@@ -169,6 +264,58 @@ type EventPic struct {
 	PicMd5Sum string `xml:"PicMd5Sum"`
 }
 
+//SubscribeMsgPopupEvent 用户操作订阅通知弹窗事件中的单个模板
+type SubscribeMsgPopupEvent struct {
+	TemplateID            string `xml:"TemplateId"`
+	SubscribeStatusString string `xml:"SubscribeStatusString"` //accept 表示用户同意订阅，reject 表示用户拒绝订阅
+	PopupScene            int    `xml:"PopupScene"`            //弹窗场景，0 为在图文内容中，1 为在公众号菜单中，2 为在公众号消息中
+}
+
+//SubscribeMsgChangeEvent 用户管理订阅通知事件中的单个模板
+type SubscribeMsgChangeEvent struct {
+	TemplateID            string `xml:"TemplateId"`
+	SubscribeStatusString string `xml:"SubscribeStatusString"` //reject 表示用户拒绝订阅
+}
+
+//SubscribeMsgSentEvent 发送订阅通知事件中的单条结果
+type SubscribeMsgSentEvent struct {
+	TemplateID  string `xml:"TemplateId"`
+	MsgID       string `xml:"MsgID"`
+	ErrorCode   int    `xml:"ErrorCode"`   //推送结果状态码，0 表示成功
+	ErrorStatus string `xml:"ErrorStatus"` //推送结果状态码文字含义
+}
+
+//CopyrightCheckResult 群发图文的原创校验结果
+type CopyrightCheckResult struct {
+	Count      int `xml:"Count"`
+	ResultList []struct {
+		ArticleIdx            int    `xml:"ArticleIdx"`
+		UserDeclareState      int    `xml:"UserDeclareState"`
+		AuditState            int    `xml:"AuditState"`
+		OriginalArticleURL    string `xml:"OriginalArticleUrl"`
+		OriginalArticleType   int    `xml:"OriginalArticleType"`
+		CanReprint            int    `xml:"CanReprint"`
+		NeedReplaceContent    int    `xml:"NeedReplaceContent"`
+		NeedShowReprintSource int    `xml:"NeedShowReprintSource"`
+	} `xml:"ResultList>item"`
+	CheckState int `xml:"CheckState"` //整体校验结果，1 未被判为转载，可以群发，2 被判为转载，可以群发，3 被判为转载，不能群发
+}
+
+//PublishEventInfo 发布任务完成事件
+type PublishEventInfo struct {
+	PublishID     int64                     `xml:"publish_id"`
+	PublishStatus freepublish.PublishStatus `xml:"publish_status"` //0 成功，1 发布中，2 原创失败，3 常规失败，4 平台审核不通过，5 成功后用户删除所有文章，6 成功后系统封禁所有文章
+	ArticleID     string                    `xml:"article_id"`
+	ArticleDetail struct {
+		Count int `xml:"count"`
+		Item  []struct {
+			Idx        int    `xml:"idx"`
+			ArticleURL string `xml:"article_url"`
+		} `xml:"item"`
+	} `xml:"article_detail"`
+	FailIdx []int `xml:"fail_idx"`
+}
+
 //EncryptedXMLMsg 安全模式下的消息体
 type EncryptedXMLMsg struct {
 	XMLName      struct{} `xml:"xml" json:"-"`
@@ -0,0 +1,117 @@
+package message
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeMsgEvent(t *testing.T) {
+	raw := `<xml>
+<ToUserName><![CDATA[gh_123456789abc]]></ToUserName>
+<FromUserName><![CDATA[otFpruAK8D-E6EfStSYonYSBZ8_4]]></FromUserName>
+<CreateTime>1610969440</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[subscribe_msg_popup_event]]></Event>
+<SubscribeMsgPopupEvent>
+<List>
+<TemplateId><![CDATA[VRR0UEO9VJOLs0MHlU0OilqX6MVFDwH3_3gz3Oc0NIc]]></TemplateId>
+<SubscribeStatusString><![CDATA[accept]]></SubscribeStatusString>
+<PopupScene>2</PopupScene>
+</List>
+<List>
+<TemplateId><![CDATA[9nLIlbOQZC5Y89AZteFEux3WCXRRRG5Wfzkpssu4bLI]]></TemplateId>
+<SubscribeStatusString><![CDATA[reject]]></SubscribeStatusString>
+<PopupScene>2</PopupScene>
+</List>
+</SubscribeMsgPopupEvent>
+</xml>`
+	var msg MixMessage
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &msg))
+	assert.Equal(t, EventType(EventSubscribeMsgPopupEvent), msg.Event)
+	assert.Len(t, msg.SubscribeMsgPopupEvent, 2)
+	assert.Equal(t, "accept", msg.SubscribeMsgPopupEvent[0].SubscribeStatusString)
+	assert.Equal(t, "9nLIlbOQZC5Y89AZteFEux3WCXRRRG5Wfzkpssu4bLI", msg.SubscribeMsgPopupEvent[1].TemplateID)
+	assert.Equal(t, 2, msg.SubscribeMsgPopupEvent[1].PopupScene)
+}
+
+func TestPublishJobFinishEvent(t *testing.T) {
+	raw := `<xml>
+<ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+<FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+<CreateTime>1481013459</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[PUBLISHJOBFINISH]]></Event>
+<PublishEventInfo>
+<publish_id>2247503051</publish_id>
+<publish_status>0</publish_status>
+<article_id><![CDATA[b5O2OUs25HBxRceL7hfReg-U9QGeq9zQjiDvy]]></article_id>
+<article_detail>
+<count>1</count>
+<item>
+<idx>1</idx>
+<article_url><![CDATA[ARTICLE_URL]]></article_url>
+</item>
+</article_detail>
+</PublishEventInfo>
+</xml>`
+	var msg MixMessage
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &msg))
+	assert.Equal(t, EventType(EventPublishJobFinish), msg.Event)
+	assert.Equal(t, int64(2247503051), msg.PublishEventInfo.PublishID)
+	assert.Equal(t, "b5O2OUs25HBxRceL7hfReg-U9QGeq9zQjiDvy", msg.PublishEventInfo.ArticleID)
+	assert.Equal(t, 1, msg.PublishEventInfo.ArticleDetail.Count)
+	assert.Equal(t, "ARTICLE_URL", msg.PublishEventInfo.ArticleDetail.Item[0].ArticleURL)
+}
+
+func TestMassSendJobFinishEvent(t *testing.T) {
+	raw := `<xml>
+<ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+<FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+<CreateTime>1481013459</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[MASSSENDJOBFINISH]]></Event>
+<MsgID>1000001625</MsgID>
+<Status><![CDATA[err(30003)]]></Status>
+<TotalCount>0</TotalCount>
+<FilterCount>0</FilterCount>
+<SentCount>0</SentCount>
+<ErrorCount>0</ErrorCount>
+<CopyrightCheckResult>
+<Count>2</Count>
+<ResultList>
+<item>
+<ArticleIdx>1</ArticleIdx>
+<UserDeclareState>0</UserDeclareState>
+<AuditState>2</AuditState>
+<OriginalArticleUrl><![CDATA[Url_1]]></OriginalArticleUrl>
+<OriginalArticleType>1</OriginalArticleType>
+<CanReprint>1</CanReprint>
+<NeedReplaceContent>1</NeedReplaceContent>
+<NeedShowReprintSource>1</NeedShowReprintSource>
+</item>
+<item>
+<ArticleIdx>2</ArticleIdx>
+<UserDeclareState>0</UserDeclareState>
+<AuditState>2</AuditState>
+<OriginalArticleUrl><![CDATA[Url_2]]></OriginalArticleUrl>
+<OriginalArticleType>1</OriginalArticleType>
+<CanReprint>1</CanReprint>
+<NeedReplaceContent>1</NeedReplaceContent>
+<NeedShowReprintSource>1</NeedShowReprintSource>
+</item>
+</ResultList>
+<CheckState>2</CheckState>
+</CopyrightCheckResult>
+</xml>`
+	var msg MixMessage
+	assert.Nil(t, xml.Unmarshal([]byte(raw), &msg))
+	assert.Equal(t, EventType(EventMassSendJobFinish), msg.Event)
+	assert.Equal(t, int64(1000001625), msg.TemplateMsgID)
+	assert.Equal(t, "err(30003)", msg.Status)
+	assert.Equal(t, 2, msg.CopyrightCheckResult.Count)
+	assert.Len(t, msg.CopyrightCheckResult.ResultList, 2)
+	assert.Equal(t, "Url_2", msg.CopyrightCheckResult.ResultList[1].OriginalArticleURL)
+	assert.Equal(t, 2, msg.CopyrightCheckResult.CheckState)
+}
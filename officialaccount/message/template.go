@@ -2,29 +2,57 @@ package message
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/silenceper/wechat/v2/credential"
 	"github.com/silenceper/wechat/v2/officialaccount/context"
 	"github.com/silenceper/wechat/v2/util"
 )
 
 const (
-	templateSendURL = "https://api.weixin.qq.com/cgi-bin/message/template/send"
-	templateListURL = "https://api.weixin.qq.com/cgi-bin/template/get_all_private_template"
+	templateSendURL        = "https://api.weixin.qq.com/cgi-bin/message/template/send"
+	templateListURL        = "https://api.weixin.qq.com/cgi-bin/template/get_all_private_template"
+	templateSetIndustryURL = "https://api.weixin.qq.com/cgi-bin/template/api_set_industry"
+	templateGetIndustryURL = "https://api.weixin.qq.com/cgi-bin/template/get_industry"
+	templateAddURL         = "https://api.weixin.qq.com/cgi-bin/template/api_add_template"
+	templateDelURL         = "https://api.weixin.qq.com/cgi-bin/template/del_private_template"
 )
 
+//templateKeysCacheExpire 模板字段缓存时间
+const templateKeysCacheExpire = 10 * time.Minute
+
+var templateKeyRegexp = regexp.MustCompile(`\{\{\s*(\w+)\.DATA\s*\}\}`)
+
 //Template 模板消息
 type Template struct {
 	*context.Context
+
+	appID        string
+	skipValidate bool
 }
 
 //NewTemplate 实例化
 func NewTemplate(context *context.Context) *Template {
 	tpl := new(Template)
 	tpl.Context = context
+	tpl.appID = context.AppID
 	return tpl
 }
 
+//SetAppID 设置模板缓存所属的appID，第三方平台代公众号处理时应设置为授权方appID
+func (tpl *Template) SetAppID(appID string) {
+	tpl.appID = appID
+}
+
+//SkipValidate 发送前不校验模板数据
+func (tpl *Template) SkipValidate(skip bool) {
+	tpl.skipValidate = skip
+}
+
 //TemplateMessage 发送的模板消息内容
 type TemplateMessage struct {
 	ToUser     string                       `json:"touser"`          // 必须, 接受者OpenID
@@ -51,8 +79,14 @@ type resTemplateSend struct {
 	MsgID int64 `json:"msgid"`
 }
 
-//Send 发送模板消息
+//Send 发送模板消息，发送前会根据模板内容校验 Data 是否包含所有字段
+//校验是尽力而为的：模板列表获取失败时跳过校验直接发送，不会因此导致发送失败
 func (tpl *Template) Send(msg *TemplateMessage) (msgID int64, err error) {
+	if !tpl.skipValidate {
+		if err = tpl.validateData(msg, true); err != nil {
+			return
+		}
+	}
 	var accessToken string
 	accessToken, err = tpl.GetAccessToken()
 	if err != nil {
@@ -106,10 +140,208 @@ func (tpl *Template) List() (templateList []*TemplateItem, err error) {
 		return
 	}
 	var res resTemplateList
-	err = util.DecodeWithError(response, res, "ListTemplate")
+	err = util.DecodeWithError(response, &res, "ListTemplate")
 	if err != nil {
 		return
 	}
 	templateList = res.TemplateList
 	return
 }
+
+//ParseTemplateKeys 解析模板内容中的字段，如 {{first.DATA}} 解析为 first
+func ParseTemplateKeys(content string) []string {
+	matches := templateKeyRegexp.FindAllStringSubmatch(content, -1)
+	keys := make([]string, 0, len(matches))
+	exists := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		if exists[match[1]] {
+			continue
+		}
+		exists[match[1]] = true
+		keys = append(keys, match[1])
+	}
+	return keys
+}
+
+//ValidateData 根据模板内容校验 Data 是否包含模板所需的全部字段
+func (tpl *Template) ValidateData(msg *TemplateMessage) error {
+	return tpl.validateData(msg, false)
+}
+
+//validateData bestEffort 为true时，模板列表获取失败或列表中找不到该模板都跳过字段校验，交由微信判断
+func (tpl *Template) validateData(msg *TemplateMessage, bestEffort bool) error {
+	if msg.ToUser == "" || msg.TemplateID == "" {
+		return fmt.Errorf("template msg touser or template_id is empty")
+	}
+	keys, err := tpl.getTemplateKeys(msg.TemplateID)
+	if err != nil {
+		if bestEffort {
+			return nil
+		}
+		if err == errTemplateNotFound {
+			return fmt.Errorf("template_id %s not found", msg.TemplateID)
+		}
+		return err
+	}
+	var missing []string
+	for _, key := range keys {
+		if item, ok := msg.Data[key]; !ok || item == nil {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("template msg data missing keys: %s", strings.Join(missing, ","))
+	}
+	return nil
+}
+
+//errTemplateNotFound 模板列表中不存在该模板
+var errTemplateNotFound = errors.New("template not found")
+
+func (tpl *Template) templateKeysCacheKey() string {
+	return fmt.Sprintf("%s_template_keys_%s", credential.CacheKeyOfficialAccountPrefix, tpl.appID)
+}
+
+//getTemplateKeys 获取模板所需的字段，优先从缓存中读取，缓存中不存在该模板时重新拉取，
+//以便发现在公众平台后台新添加的模板；缓存写入失败不影响返回结果
+func (tpl *Template) getTemplateKeys(templateID string) ([]string, error) {
+	cacheKey := tpl.templateKeysCacheKey()
+	if tpl.Cache != nil {
+		var cached map[string][]string
+		if val, ok := tpl.Cache.Get(cacheKey).(string); ok && json.Unmarshal([]byte(val), &cached) == nil {
+			if keys, ok := cached[templateID]; ok {
+				return keys, nil
+			}
+		}
+	}
+	templateList, err := tpl.List()
+	if err != nil {
+		return nil, err
+	}
+	templateKeys := make(map[string][]string, len(templateList))
+	for _, item := range templateList {
+		templateKeys[item.TemplateID] = ParseTemplateKeys(item.Content)
+	}
+	if tpl.Cache != nil {
+		data, _ := json.Marshal(templateKeys)
+		_ = tpl.Cache.Set(cacheKey, string(data), templateKeysCacheExpire)
+	}
+	keys, ok := templateKeys[templateID]
+	if !ok {
+		return nil, errTemplateNotFound
+	}
+	return keys, nil
+}
+
+//clearTemplateKeysCache 模板增删后清除字段缓存
+func (tpl *Template) clearTemplateKeysCache() {
+	if tpl.Cache != nil {
+		_ = tpl.Cache.Delete(tpl.templateKeysCacheKey())
+	}
+}
+
+//IndustryClass 行业分类
+type IndustryClass struct {
+	FirstClass  string `json:"first_class"`
+	SecondClass string `json:"second_class"`
+}
+
+//Industry 设置的行业信息
+type Industry struct {
+	util.CommonError
+
+	PrimaryIndustry   IndustryClass `json:"primary_industry"`
+	SecondaryIndustry IndustryClass `json:"secondary_industry"`
+}
+
+//SetIndustry 设置所属行业，industryID 参考行业代码表
+func (tpl *Template) SetIndustry(industryID1, industryID2 string) (err error) {
+	var accessToken string
+	accessToken, err = tpl.GetAccessToken()
+	if err != nil {
+		return
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", templateSetIndustryURL, accessToken)
+	var response []byte
+	response, err = util.PostJSON(uri, map[string]string{
+		"industry_id1": industryID1,
+		"industry_id2": industryID2,
+	})
+	if err != nil {
+		return
+	}
+	return util.DecodeWithCommonError(response, "SetIndustry")
+}
+
+//GetIndustry 获取设置的行业信息
+func (tpl *Template) GetIndustry() (industry *Industry, err error) {
+	var accessToken string
+	accessToken, err = tpl.GetAccessToken()
+	if err != nil {
+		return
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", templateGetIndustryURL, accessToken)
+	var response []byte
+	response, err = util.HTTPGet(uri)
+	if err != nil {
+		return
+	}
+	industry = new(Industry)
+	err = util.DecodeWithError(response, industry, "GetIndustry")
+	return
+}
+
+type resTemplateAdd struct {
+	util.CommonError
+
+	TemplateID string `json:"template_id"`
+}
+
+//Add 从模板库中添加模板，shortID 为模板库中模板的编号，keywordNames 为选用的类目模板的关键词
+func (tpl *Template) Add(shortID string, keywordNames ...string) (templateID string, err error) {
+	var accessToken string
+	accessToken, err = tpl.GetAccessToken()
+	if err != nil {
+		return
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", templateAddURL, accessToken)
+	req := map[string]interface{}{
+		"template_id_short": shortID,
+	}
+	if len(keywordNames) > 0 {
+		req["keyword_name_list"] = keywordNames
+	}
+	var response []byte
+	response, err = util.PostJSON(uri, req)
+	if err != nil {
+		return
+	}
+	var res resTemplateAdd
+	err = util.DecodeWithError(response, &res, "AddTemplate")
+	templateID = res.TemplateID
+	if err == nil {
+		tpl.clearTemplateKeysCache()
+	}
+	return
+}
+
+//Delete 删除模板
+func (tpl *Template) Delete(templateID string) (err error) {
+	var accessToken string
+	accessToken, err = tpl.GetAccessToken()
+	if err != nil {
+		return
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", templateDelURL, accessToken)
+	var response []byte
+	response, err = util.PostJSON(uri, map[string]string{
+		"template_id": templateID,
+	})
+	if err != nil {
+		return
+	}
+	if err = util.DecodeWithCommonError(response, "DeleteTemplate"); err == nil {
+		tpl.clearTemplateKeysCache()
+	}
+	return
+}
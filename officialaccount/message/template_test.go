@@ -0,0 +1,98 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/officialaccount/config"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestParseTemplateKeys(t *testing.T) {
+	content := "{{first.DATA}}\n订单号：{{keyword1.DATA}}\n金额：{{ keyword2.DATA }}\n{{keyword1.DATA}}\n{{remark.DATA}}"
+	assert.Equal(t, []string{"first", "keyword1", "keyword2", "remark"}, ParseTemplateKeys(content))
+	assert.Equal(t, []string{}, ParseTemplateKeys("no keys"))
+}
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func newTestTemplate() *Template {
+	return NewTemplate(&context.Context{
+		Config:            &config.Config{AppID: "wx123", Cache: cache.NewMemory()},
+		AccessTokenHandle: mockAccessToken{},
+	})
+}
+
+func TestTemplateValidateData(t *testing.T) {
+	defer gock.Off()
+	gock.New(templateListURL).Times(2).Reply(200).JSON(map[string]interface{}{
+		"template_list": []map[string]string{{"template_id": "tpl1", "content": "{{first.DATA}}{{remark.DATA}}"}},
+	})
+
+	tpl := newTestTemplate()
+	msg := &TemplateMessage{ToUser: "openid", TemplateID: "tpl1", Data: map[string]*TemplateDataItem{"first": {Value: "hi"}}}
+	assert.NotNil(t, tpl.ValidateData(msg))
+	msg.Data["remark"] = &TemplateDataItem{Value: "bye"}
+	assert.Nil(t, tpl.ValidateData(msg))
+
+	//缓存中不存在的模板会重新拉取列表
+	msg.TemplateID = "unknown"
+	assert.NotNil(t, tpl.ValidateData(msg))
+	assert.True(t, gock.IsDone())
+}
+
+func TestTemplateSendWithTemplateAddedInConsole(t *testing.T) {
+	defer gock.Off()
+	gock.New(templateListURL).Reply(200).JSON(map[string]interface{}{
+		"template_list": []map[string]string{{"template_id": "tpl1", "content": "{{first.DATA}}"}},
+	})
+	//在公众平台后台添加 tpl2 后，列表中才出现该模板
+	gock.New(templateListURL).Reply(200).JSON(map[string]interface{}{
+		"template_list": []map[string]string{
+			{"template_id": "tpl1", "content": "{{first.DATA}}"},
+			{"template_id": "tpl2", "content": "{{remark.DATA}}"},
+		},
+	})
+	gock.New(templateSendURL).Times(2).Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "msgid": 100})
+
+	tpl := newTestTemplate()
+	_, err := tpl.Send(&TemplateMessage{ToUser: "openid", TemplateID: "tpl1", Data: map[string]*TemplateDataItem{"first": {Value: "hi"}}})
+	assert.Nil(t, err)
+
+	msgID, err := tpl.Send(&TemplateMessage{ToUser: "openid", TemplateID: "tpl2", Data: map[string]*TemplateDataItem{"remark": {Value: "bye"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), msgID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestTemplateSendSkipsValidationWhenTemplateNotFound(t *testing.T) {
+	defer gock.Off()
+	gock.New(templateListURL).Reply(200).JSON(map[string]interface{}{"template_list": []map[string]string{}})
+	gock.New(templateSendURL).Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "msgid": 100})
+
+	tpl := newTestTemplate()
+	msgID, err := tpl.Send(&TemplateMessage{ToUser: "openid", TemplateID: "tpl3"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), msgID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestTemplateSendSkipsValidationWhenListFails(t *testing.T) {
+	defer gock.Off()
+	gock.New(templateListURL).Times(2).Reply(200).JSON(map[string]interface{}{"errcode": 45009, "errmsg": "api freq out of limit"})
+	gock.New(templateSendURL).Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "msgid": 100})
+
+	tpl := newTestTemplate()
+	msg := &TemplateMessage{ToUser: "openid", TemplateID: "tpl1"}
+	assert.NotNil(t, tpl.ValidateData(msg))
+
+	msgID, err := tpl.Send(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), msgID)
+}
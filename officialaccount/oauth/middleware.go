@@ -0,0 +1,270 @@
+package oauth
+
+import (
+	stdcontext "context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/silenceper/wechat/v2/credential"
+)
+
+const (
+	// ScopeBase 静默授权，只能获取openid
+	ScopeBase = "snsapi_base"
+	// ScopeUserInfo 需用户确认，可获取用户基本信息
+	ScopeUserInfo = "snsapi_userinfo"
+
+	// DefaultStateTTL state 默认有效期
+	DefaultStateTTL = 10 * time.Minute
+	// DefaultRefreshAhead access_token 提前刷新的时间
+	DefaultRefreshAhead = 5 * time.Minute
+	// DefaultStateCookieName 保存state摘要的cookie名
+	DefaultStateCookieName = "wechat_oauth_state"
+)
+
+var (
+	// ErrInvalidState state 不存在、已过期或与发起授权的浏览器不一致，可能是伪造的回调
+	ErrInvalidState = errors.New("oauth state is invalid or expired")
+	// ErrAuthDenied 用户拒绝授权
+	ErrAuthDenied = errors.New("oauth authorization denied by user")
+)
+
+type sessionContextKey struct{}
+
+// SessionFromContext 从请求的context中读取 Middleware 注入的会话
+func SessionFromContext(ctx stdcontext.Context) (*Session, bool) {
+	sess, ok := ctx.Value(sessionContextKey{}).(*Session)
+	return sess, ok
+}
+
+// Middleware 网页授权中间件：未授权时跳转到微信授权页，回调时校验state并换取access_token，
+// 之后通过 SessionFromContext 可读取当前用户的会话
+type Middleware struct {
+	oauth *Oauth
+	scope string
+	store SessionStore
+
+	// RedirectURI 授权回调地址，必须同样经过该中间件，为空时使用当前请求地址
+	RedirectURI string
+	// StateTTL state 有效期
+	StateTTL time.Duration
+	// StateCookieName 发起授权时写入浏览器的state cookie，回调时必须与之匹配以防止登录CSRF
+	StateCookieName string
+	// TrustForwardedHeaders 是否信任 X-Forwarded-Proto 与 X-Forwarded-Host 来还原原始请求地址，
+	// 仅在服务部署于可信反向代理之后时开启
+	TrustForwardedHeaders bool
+	// RefreshAhead access_token 在过期前多久刷新
+	RefreshAhead time.Duration
+	// OnError 授权失败时的处理方法，默认返回401
+	OnError func(writer http.ResponseWriter, req *http.Request, err error)
+}
+
+// NewMiddleware 实例化网页授权中间件，scope 为 ScopeBase 或 ScopeUserInfo
+func (oauth *Oauth) NewMiddleware(scope string, store SessionStore) *Middleware {
+	return &Middleware{
+		oauth:           oauth,
+		scope:           scope,
+		store:           store,
+		StateTTL:        DefaultStateTTL,
+		StateCookieName: DefaultStateCookieName,
+		RefreshAhead:    DefaultRefreshAhead,
+		OnError: func(writer http.ResponseWriter, req *http.Request, err error) {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+		},
+	}
+}
+
+// Handler 包装需要网页授权的 http.Handler，授权回调的判断规则见 isCallback
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if m.isCallback(req) {
+			query := req.URL.Query()
+			m.callback(writer, req, query.Get("state"), query.Get("code"))
+			return
+		}
+		sess, err := m.session(writer, req)
+		if err != nil || sess == nil {
+			m.authorize(writer, req)
+			return
+		}
+		next.ServeHTTP(writer, req.WithContext(stdcontext.WithValue(req.Context(), sessionContextKey{}, sess)))
+	})
+}
+
+// isCallback 设置了 RedirectURI 时，路径与之相同且携带state的请求为授权回调；
+// 否则只有同时携带code与state的请求才视为回调，避免业务自身的state参数被误判
+func (m *Middleware) isCallback(req *http.Request) bool {
+	query := req.URL.Query()
+	if query.Get("state") == "" {
+		return false
+	}
+	if m.RedirectURI != "" {
+		if u, err := url.Parse(m.RedirectURI); err == nil && u.Path == req.URL.Path {
+			return true
+		}
+	}
+	return query.Get("code") != ""
+}
+
+// session 读取会话，access_token 即将过期时使用refresh_token刷新
+func (m *Middleware) session(writer http.ResponseWriter, req *http.Request) (*Session, error) {
+	sess, err := m.store.Load(req)
+	if err != nil || sess == nil {
+		return nil, err
+	}
+	if m.scope == ScopeUserInfo && sess.UserInfo == nil {
+		return nil, nil
+	}
+	if !sess.AccessTokenExpired(m.RefreshAhead) {
+		return sess, nil
+	}
+	token, err := m.oauth.RefreshAccessToken(sess.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	sess.update(token)
+	if err = m.store.Save(writer, req, sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// authorize 生成state并跳转到微信授权页，state 对应的cache中保存原始请求地址，
+// 同时将state摘要写入cookie，使回调只能在发起授权的浏览器中完成
+func (m *Middleware) authorize(writer http.ResponseWriter, req *http.Request) {
+	state, err := randomToken()
+	if err != nil {
+		m.OnError(writer, req, err)
+		return
+	}
+	current := m.requestURL(req)
+	if err = m.oauth.Cache.Set(m.stateCacheKey(state), current, m.StateTTL); err != nil {
+		m.OnError(writer, req, err)
+		return
+	}
+	http.SetCookie(writer, &http.Cookie{
+		Name:     m.StateCookieName,
+		Value:    hashState(state),
+		Path:     "/",
+		MaxAge:   int(m.StateTTL.Seconds()),
+		Secure:   m.scheme(req) == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	redirectURI := m.RedirectURI
+	if redirectURI == "" {
+		redirectURI = current
+	}
+	if err = m.oauth.Redirect(writer, req, redirectURI, m.scope, state); err != nil {
+		m.OnError(writer, req, err)
+	}
+}
+
+// callback 校验state及state cookie，换取access_token并保存会话，最后跳转回原始请求地址
+func (m *Middleware) callback(writer http.ResponseWriter, req *http.Request, state, code string) {
+	cookie, err := req.Cookie(m.StateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(hashState(state))) != 1 {
+		m.OnError(writer, req, ErrInvalidState)
+		return
+	}
+	http.SetCookie(writer, &http.Cookie{
+		Name:     m.StateCookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	cacheKey := m.stateCacheKey(state)
+	returnURL, ok := m.oauth.Cache.Get(cacheKey).(string)
+	if !ok {
+		m.OnError(writer, req, ErrInvalidState)
+		return
+	}
+	if err = m.oauth.Cache.Delete(cacheKey); err != nil {
+		m.OnError(writer, req, err)
+		return
+	}
+	if code == "" {
+		m.OnError(writer, req, ErrAuthDenied)
+		return
+	}
+	sess, err := m.exchange(code)
+	if err == nil {
+		err = m.store.Save(writer, req, sess)
+	}
+	if err != nil {
+		m.OnError(writer, req, err)
+		return
+	}
+	http.Redirect(writer, req, returnURL, http.StatusFound)
+}
+
+func (m *Middleware) exchange(code string) (*Session, error) {
+	token, err := m.oauth.GetUserAccessToken(code)
+	if err != nil {
+		return nil, err
+	}
+	sess := new(Session)
+	sess.update(token)
+	if m.scope != ScopeUserInfo {
+		return sess, nil
+	}
+	userInfo, err := m.oauth.GetUserInfo(token.AccessToken, token.OpenID)
+	if err != nil {
+		return nil, err
+	}
+	sess.UserInfo = &userInfo
+	if sess.UnionID == "" {
+		sess.UnionID = userInfo.Unionid
+	}
+	return sess, nil
+}
+
+func (m *Middleware) stateCacheKey(state string) string {
+	return fmt.Sprintf("%s_oauth_state_%s_%s", credential.CacheKeyOfficialAccountPrefix, m.oauth.AppID, state)
+}
+
+// hashState cookie 中只保存state的摘要
+func hashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *Middleware) scheme(req *http.Request) string {
+	if m.TrustForwardedHeaders {
+		if proto := req.Header.Get("X-Forwarded-Proto"); proto != "" {
+			return proto
+		}
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// requestURL 还原当前请求的完整地址，并去掉授权回调的code与state参数
+func (m *Middleware) requestURL(req *http.Request) string {
+	host := req.Host
+	if m.TrustForwardedHeaders {
+		if forwarded := req.Header.Get("X-Forwarded-Host"); forwarded != "" {
+			host = forwarded
+		}
+	}
+	u := url.URL{
+		Scheme: m.scheme(req),
+		Host:   host,
+		Path:   req.URL.Path,
+	}
+	query := req.URL.Query()
+	query.Del("code")
+	query.Del("state")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
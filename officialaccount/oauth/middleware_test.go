@@ -0,0 +1,175 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/officialaccount/config"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestOauth() *Oauth {
+	return NewOauth(&context.Context{
+		Config: &config.Config{AppID: "wx123", AppSecret: "secret", Cache: cache.NewMemory()},
+	})
+}
+
+func sessionStoreRoundTrip(t *testing.T, store SessionStore) {
+	sess := &Session{OpenID: "openid1", AccessToken: "token", ExpireAt: time.Now().Add(time.Hour).Unix()}
+	rec := httptest.NewRecorder()
+	assert.Nil(t, store.Save(rec, httptest.NewRequest(http.MethodGet, "/", nil), sess))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	loaded, err := store.Load(req)
+	assert.Nil(t, err)
+	assert.Equal(t, sess, loaded)
+
+	loaded, err = store.Load(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Nil(t, err)
+	assert.Nil(t, loaded)
+}
+
+func TestCookieStore(t *testing.T) {
+	store := NewCookieStore("test-secret")
+	sessionStoreRoundTrip(t, store)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: DefaultSessionCookieName, Value: "tampered"})
+	_, err := store.Load(req)
+	assert.Equal(t, ErrInvalidSession, err)
+}
+
+func TestCacheStore(t *testing.T) {
+	sessionStoreRoundTrip(t, NewCacheStore(cache.NewMemory()))
+}
+
+// authorizeState 发起授权，返回state与写入浏览器的state cookie
+func authorizeState(t *testing.T, handler http.Handler, target string) (string, *http.Cookie) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	assert.Equal(t, http.StatusFound, rec.Code)
+	location := rec.Header().Get("Location")
+	assert.True(t, strings.HasPrefix(location, "https://open.weixin.qq.com/connect/oauth2/authorize?appid=wx123"))
+
+	u, err := url.Parse(strings.TrimSuffix(location, "#wechat_redirect"))
+	assert.Nil(t, err)
+	state := u.Query().Get("state")
+	assert.NotEmpty(t, state)
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, DefaultStateCookieName, cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	return state, cookies[0]
+}
+
+func TestMiddlewareAuthorize(t *testing.T) {
+	oauth := newTestOauth()
+	m := oauth.NewMiddleware(ScopeBase, NewCookieStore("test-secret"))
+	m.RedirectURI = "http://example.com/oauth/callback"
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unauthorized request should not reach handler")
+	}))
+
+	state, cookie := authorizeState(t, handler, "http://example.com/profile?a=1")
+	assert.Equal(t, "http://example.com/profile?a=1", oauth.Cache.Get(m.stateCacheKey(state)))
+
+	//用户拒绝授权时没有code
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/oauth/callback?state="+state, nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), ErrAuthDenied.Error())
+	assert.Equal(t, -1, rec.Result().Cookies()[0].MaxAge)
+
+	//state 只能使用一次
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/oauth/callback?code=x&state="+state, nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), ErrInvalidState.Error())
+}
+
+func TestMiddlewareCallbackWithoutStateCookie(t *testing.T) {
+	oauth := newTestOauth()
+	m := oauth.NewMiddleware(ScopeBase, NewCookieStore("test-secret"))
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unauthorized request should not reach handler")
+	}))
+
+	//攻击者自己发起授权，得到合法的state，再诱导受害者打开回调地址
+	state, _ := authorizeState(t, handler, "http://example.com/profile")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/profile?code=attacker-code&state="+state, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), ErrInvalidState.Error())
+
+	//其他浏览器的state cookie同样不被接受
+	_, otherCookie := authorizeState(t, handler, "http://example.com/profile")
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/profile?code=attacker-code&state="+state, nil)
+	req.AddCookie(otherCookie)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), ErrInvalidState.Error())
+	assert.NotNil(t, oauth.Cache.Get(m.stateCacheKey(state)))
+}
+
+func TestMiddlewareStateQueryIsNotCallback(t *testing.T) {
+	m := newTestOauth().NewMiddleware(ScopeBase, NewCookieStore("test-secret"))
+	m.RedirectURI = "http://example.com/oauth/callback"
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unauthorized request should not reach handler")
+	}))
+
+	//业务页面自身的state参数不应被当作授权回调
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/orders?state=paid", nil))
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Location"), "https://open.weixin.qq.com/connect/oauth2/authorize"))
+}
+
+func TestMiddlewareRequestURL(t *testing.T) {
+	m := newTestOauth().NewMiddleware(ScopeBase, NewCookieStore("test-secret"))
+	req := httptest.NewRequest(http.MethodGet, "http://internal:8080/profile?a=1&code=x&state=y", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "example.com")
+	assert.Equal(t, "http://internal:8080/profile?a=1", m.requestURL(req))
+
+	m.TrustForwardedHeaders = true
+	assert.Equal(t, "https://example.com/profile?a=1", m.requestURL(req))
+}
+
+func TestMiddlewareSession(t *testing.T) {
+	store := NewCookieStore("test-secret")
+	m := newTestOauth().NewMiddleware(ScopeBase, store)
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess, ok := SessionFromContext(r.Context())
+		assert.True(t, ok)
+		_, _ = w.Write([]byte(sess.OpenID))
+	}))
+
+	rec := httptest.NewRecorder()
+	sess := &Session{OpenID: "openid1", ExpireAt: time.Now().Add(time.Hour).Unix()}
+	assert.Nil(t, store.Save(rec, httptest.NewRequest(http.MethodGet, "/", nil), sess))
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "openid1", rec.Body.String())
+}
@@ -0,0 +1,240 @@
+package oauth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/credential"
+)
+
+const (
+	// DefaultSessionCookieName 默认保存会话的cookie名
+	DefaultSessionCookieName = "wechat_oauth"
+	// DefaultSessionTTL 会话默认有效期，与refresh_token的有效期一致
+	DefaultSessionTTL = 30 * 24 * time.Hour
+)
+
+// ErrInvalidSession 会话数据无法解析或已被篡改
+var ErrInvalidSession = errors.New("oauth session is invalid")
+
+// Session 网页授权会话
+type Session struct {
+	OpenID       string    `json:"openid"`
+	UnionID      string    `json:"unionid,omitempty"`
+	Scope        string    `json:"scope"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpireAt     int64     `json:"expire_at"`          // access_token 过期时间
+	UserInfo     *UserInfo `json:"userinfo,omitempty"` // scope 为 snsapi_userinfo 时的用户信息
+}
+
+// AccessTokenExpired access_token 是否会在 ahead 时间内过期
+func (sess *Session) AccessTokenExpired(ahead time.Duration) bool {
+	return time.Now().Add(ahead).Unix() >= sess.ExpireAt
+}
+
+func (sess *Session) update(token ResAccessToken) {
+	sess.OpenID = token.OpenID
+	sess.Scope = token.Scope
+	sess.AccessToken = token.AccessToken
+	sess.RefreshToken = token.RefreshToken
+	sess.ExpireAt = time.Now().Unix() + token.ExpiresIn
+	if token.UnionID != "" {
+		sess.UnionID = token.UnionID
+	}
+}
+
+// SessionStore 会话存储
+type SessionStore interface {
+	// Load 读取当前请求的会话，不存在时返回 nil, nil
+	Load(req *http.Request) (*Session, error)
+	// Save 保存会话
+	Save(writer http.ResponseWriter, req *http.Request, sess *Session) error
+	// Clear 清除会话
+	Clear(writer http.ResponseWriter, req *http.Request) error
+}
+
+// CookieOption 保存会话相关cookie的属性
+type CookieOption struct {
+	Name     string
+	Path     string
+	Domain   string
+	MaxAge   time.Duration
+	Secure   bool
+	SameSite http.SameSite
+}
+
+func (opt *CookieOption) cookie(value string) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     opt.Name,
+		Value:    value,
+		Path:     opt.Path,
+		Domain:   opt.Domain,
+		Secure:   opt.Secure,
+		HttpOnly: true,
+		SameSite: opt.SameSite,
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	if value == "" {
+		cookie.MaxAge = -1
+	} else {
+		cookie.MaxAge = int(opt.MaxAge.Seconds())
+	}
+	return cookie
+}
+
+func newCookieOption() CookieOption {
+	return CookieOption{
+		Name:     DefaultSessionCookieName,
+		MaxAge:   DefaultSessionTTL,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// CookieStore 将会话加密后保存在cookie中
+type CookieStore struct {
+	CookieOption
+	aead cipher.AEAD
+}
+
+// NewCookieStore 实例化，secret 用于加密cookie，应使用足够长的随机字符串
+func NewCookieStore(secret string) *CookieStore {
+	key := sha256.Sum256([]byte(secret))
+	block, _ := aes.NewCipher(key[:])
+	aead, _ := cipher.NewGCM(block)
+	return &CookieStore{
+		CookieOption: newCookieOption(),
+		aead:         aead,
+	}
+}
+
+// Load 读取会话
+func (store *CookieStore) Load(req *http.Request) (*Session, error) {
+	cookie, err := req.Cookie(store.Name)
+	if err != nil {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	nonceSize := store.aead.NonceSize()
+	if err != nil || len(data) < nonceSize {
+		return nil, ErrInvalidSession
+	}
+	plain, err := store.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(store.Name))
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+	sess := new(Session)
+	if err = json.Unmarshal(plain, sess); err != nil {
+		return nil, ErrInvalidSession
+	}
+	return sess, nil
+}
+
+// Save 保存会话
+func (store *CookieStore) Save(writer http.ResponseWriter, req *http.Request, sess *Session) error {
+	plain, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, store.aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := store.aead.Seal(nonce, nonce, plain, []byte(store.Name))
+	http.SetCookie(writer, store.cookie(base64.RawURLEncoding.EncodeToString(data)))
+	return nil
+}
+
+// Clear 清除会话
+func (store *CookieStore) Clear(writer http.ResponseWriter, req *http.Request) error {
+	http.SetCookie(writer, store.cookie(""))
+	return nil
+}
+
+// CacheStore 将会话保存在cache中，cookie中仅保存随机的会话ID
+type CacheStore struct {
+	CookieOption
+	cache cache.Cache
+}
+
+// NewCacheStore 实例化
+func NewCacheStore(cache cache.Cache) *CacheStore {
+	return &CacheStore{
+		CookieOption: newCookieOption(),
+		cache:        cache,
+	}
+}
+
+func (store *CacheStore) cacheKey(sessionID string) string {
+	return fmt.Sprintf("%s_oauth_session_%s", credential.CacheKeyOfficialAccountPrefix, sessionID)
+}
+
+// Load 读取会话
+func (store *CacheStore) Load(req *http.Request) (*Session, error) {
+	cookie, err := req.Cookie(store.Name)
+	if err != nil || cookie.Value == "" {
+		return nil, nil
+	}
+	val, ok := store.cache.Get(store.cacheKey(cookie.Value)).(string)
+	if !ok {
+		return nil, nil
+	}
+	sess := new(Session)
+	if err = json.Unmarshal([]byte(val), sess); err != nil {
+		return nil, ErrInvalidSession
+	}
+	return sess, nil
+}
+
+// Save 保存会话，每次保存都会生成新的会话ID
+func (store *CacheStore) Save(writer http.ResponseWriter, req *http.Request, sess *Session) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	if cookie, e := req.Cookie(store.Name); e == nil && cookie.Value != "" {
+		if err = store.cache.Delete(store.cacheKey(cookie.Value)); err != nil {
+			return err
+		}
+	}
+	sessionID, err := randomToken()
+	if err != nil {
+		return err
+	}
+	if err = store.cache.Set(store.cacheKey(sessionID), string(data), store.MaxAge); err != nil {
+		return err
+	}
+	http.SetCookie(writer, store.cookie(sessionID))
+	return nil
+}
+
+// Clear 清除会话
+func (store *CacheStore) Clear(writer http.ResponseWriter, req *http.Request) error {
+	cookie, err := req.Cookie(store.Name)
+	if err != nil || cookie.Value == "" {
+		return nil
+	}
+	http.SetCookie(writer, store.cookie(""))
+	return store.cache.Delete(store.cacheKey(cookie.Value))
+}
+
+// randomToken 生成不可预测的随机串，用于会话ID与state
+func randomToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
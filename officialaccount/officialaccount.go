@@ -2,27 +2,43 @@ package officialaccount
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/silenceper/wechat/v2/officialaccount/datacube"
 
 	"github.com/silenceper/wechat/v2/credential"
+	"github.com/silenceper/wechat/v2/ocr"
+	"github.com/silenceper/wechat/v2/officialaccount/audit"
+	"github.com/silenceper/wechat/v2/officialaccount/autoreply"
 	"github.com/silenceper/wechat/v2/officialaccount/basic"
 	"github.com/silenceper/wechat/v2/officialaccount/broadcast"
+	"github.com/silenceper/wechat/v2/officialaccount/card"
+	"github.com/silenceper/wechat/v2/officialaccount/comment"
 	"github.com/silenceper/wechat/v2/officialaccount/config"
 	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/officialaccount/conversation"
+	"github.com/silenceper/wechat/v2/officialaccount/customerservice"
 	"github.com/silenceper/wechat/v2/officialaccount/device"
+	"github.com/silenceper/wechat/v2/officialaccount/draft"
+	"github.com/silenceper/wechat/v2/officialaccount/freepublish"
+	"github.com/silenceper/wechat/v2/officialaccount/invoice"
 	"github.com/silenceper/wechat/v2/officialaccount/js"
 	"github.com/silenceper/wechat/v2/officialaccount/material"
 	"github.com/silenceper/wechat/v2/officialaccount/menu"
 	"github.com/silenceper/wechat/v2/officialaccount/message"
 	"github.com/silenceper/wechat/v2/officialaccount/oauth"
+	"github.com/silenceper/wechat/v2/officialaccount/qrlogin"
 	"github.com/silenceper/wechat/v2/officialaccount/server"
+	"github.com/silenceper/wechat/v2/officialaccount/subscribe"
 	"github.com/silenceper/wechat/v2/officialaccount/user"
+	"github.com/silenceper/wechat/v2/util"
 )
 
 //OfficialAccount 微信公众号相关API
 type OfficialAccount struct {
-	ctx *context.Context
+	ctx           *context.Context
+	replayChecker *util.ReplayChecker
+	auditSink     audit.Sink
 }
 
 //NewOfficialAccount 实例化公众号API
@@ -32,7 +48,10 @@ func NewOfficialAccount(cfg *config.Config) *OfficialAccount {
 		Config:            cfg,
 		AccessTokenHandle: defaultAkHandle,
 	}
-	return &OfficialAccount{ctx: ctx}
+	return &OfficialAccount{
+		ctx:           ctx,
+		replayChecker: util.NewReplayChecker(cfg.Cache, credential.CacheKeyOfficialAccountPrefix+cfg.AppID, 0),
+	}
 }
 
 //SetAccessTokenHandle 自定义access_token获取方式
@@ -40,6 +59,17 @@ func (officialAccount *OfficialAccount) SetAccessTokenHandle(accessTokenHandle c
 	officialAccount.ctx.AccessTokenHandle = accessTokenHandle
 }
 
+//SetAuditSink 设置消息审计，GetServer 返回的server均会启用
+func (officialAccount *OfficialAccount) SetAuditSink(sink audit.Sink) {
+	officialAccount.auditSink = sink
+}
+
+//SetReplayChecker 设置消息回调的防重放校验，GetServer 返回的server均会启用
+//默认已启用窗口为 util.DefaultReplayWindow 的校验，传nil时关闭
+func (officialAccount *OfficialAccount) SetReplayChecker(checker *util.ReplayChecker) {
+	officialAccount.replayChecker = checker
+}
+
 // GetContext get Context
 func (officialAccount *OfficialAccount) GetContext() *context.Context {
 	return officialAccount.ctx
@@ -60,6 +90,8 @@ func (officialAccount *OfficialAccount) GetServer(req *http.Request, writer http
 	srv := server.NewServer(officialAccount.ctx)
 	srv.Request = req
 	srv.Writer = writer
+	srv.SetReplayChecker(officialAccount.replayChecker)
+	srv.SetAuditSink(officialAccount.auditSink)
 	return srv
 }
 
@@ -108,3 +140,58 @@ func (officialAccount *OfficialAccount) GetBroadcast() *broadcast.Broadcast {
 func (officialAccount *OfficialAccount) GetDataCube() *datacube.DataCube {
 	return datacube.NewCube(officialAccount.ctx)
 }
+
+//GetConversation 多轮对话会话状态管理，ttl 为会话超时时间
+func (officialAccount *OfficialAccount) GetConversation(ttl time.Duration) *conversation.Conversation {
+	return conversation.NewConversation(officialAccount.ctx, ttl)
+}
+
+//GetAutoReply 本地自动回复引擎
+func (officialAccount *OfficialAccount) GetAutoReply() *autoreply.AutoReply {
+	return autoreply.NewAutoReply(officialAccount.ctx)
+}
+
+//GetCustomerService 客服帐号及会话管理
+func (officialAccount *OfficialAccount) GetCustomerService() *customerservice.CustomerService {
+	return customerservice.NewCustomerService(officialAccount.ctx)
+}
+
+//GetSubscribe 订阅通知
+func (officialAccount *OfficialAccount) GetSubscribe() *subscribe.Subscribe {
+	return subscribe.NewSubscribe(officialAccount.ctx)
+}
+
+//GetDraft 草稿箱
+func (officialAccount *OfficialAccount) GetDraft() *draft.Draft {
+	return draft.NewDraft(officialAccount.ctx)
+}
+
+//GetFreePublish 发布能力
+func (officialAccount *OfficialAccount) GetFreePublish() *freepublish.FreePublish {
+	return freepublish.NewFreePublish(officialAccount.ctx)
+}
+
+//GetComment 图文评论管理
+func (officialAccount *OfficialAccount) GetComment() *comment.Comment {
+	return comment.NewComment(officialAccount.ctx)
+}
+
+//GetQRLogin 扫码登录，expire 为登录二维码有效期
+func (officialAccount *OfficialAccount) GetQRLogin(expire time.Duration) *qrlogin.QRLogin {
+	return qrlogin.NewQRLogin(officialAccount.ctx, expire)
+}
+
+//GetCard 卡券
+func (officialAccount *OfficialAccount) GetCard() *card.Card {
+	return card.NewCard(officialAccount.ctx)
+}
+
+//GetInvoice 电子发票
+func (officialAccount *OfficialAccount) GetInvoice() *invoice.Invoice {
+	return invoice.NewInvoice(officialAccount.ctx)
+}
+
+//GetOCR 图像识别及图像处理
+func (officialAccount *OfficialAccount) GetOCR() *ocr.OCR {
+	return ocr.NewOCR(officialAccount.ctx)
+}
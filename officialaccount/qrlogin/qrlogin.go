@@ -0,0 +1,289 @@
+package qrlogin
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/credential"
+	"github.com/silenceper/wechat/v2/officialaccount/basic"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/silenceper/wechat/v2/util"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultExpire 登录二维码默认有效期
+	DefaultExpire = 5 * time.Minute
+	// DefaultPollInterval Wait 默认的轮询间隔
+	DefaultPollInterval = time.Second
+
+	// ScenePrefix 扫码登录二维码的scene_str前缀，用于与其他带参二维码区分
+	ScenePrefix = "qrlogin_"
+	// subscribeScenePrefix 未关注用户扫码关注后，事件EventKey的前缀
+	subscribeScenePrefix = "qrscene_"
+)
+
+// Status 扫码登录状态
+type Status string
+
+const (
+	// StatusPending 等待用户扫码
+	StatusPending Status = "pending"
+	// StatusConfirmed 用户已扫码
+	StatusConfirmed Status = "confirmed"
+	// StatusExpired 二维码已过期
+	StatusExpired Status = "expired"
+)
+
+// ErrSessionNotFound 登录会话不存在或已被清理
+var ErrSessionNotFound = errors.New("qrlogin session not found")
+
+// claimLock cache 未实现 cache.Adder 时用于标记登录结果已被读取，GetQRLogin 每次返回新实例，因此使用包级别的锁
+var claimLock sync.Mutex
+
+// Session 扫码登录会话
+type Session struct {
+	Scene       string `json:"scene"`
+	Status      Status `json:"status"`
+	OpenID      string `json:"openid,omitempty"`
+	Ticket      string `json:"ticket"`
+	URL         string `json:"url"`          // 二维码图片解析后的地址，可自行生成二维码
+	QRCodeURL   string `json:"qrcode_url"`   // 通过ticket换取的二维码图片地址
+	CreatedAt   int64  `json:"created_at"`   // 创建时间
+	ExpireAt    int64  `json:"expire_at"`    // 过期时间
+	ConfirmedAt int64  `json:"confirmed_at"` // 扫码时间
+}
+
+// Expired 二维码是否已过期且用户未扫码
+func (sess *Session) Expired() bool {
+	return sess.Status != StatusConfirmed && sess.ExpireAt < time.Now().Unix()
+}
+
+// QRLogin 公众号扫码登录：为每个网页会话生成带参临时二维码，通过关注/扫码事件确认用户身份
+type QRLogin struct {
+	*context.Context
+
+	appID  string
+	expire time.Duration
+
+	// PollInterval Wait 的轮询间隔
+	PollInterval time.Duration
+	// ConfirmReply 用户扫码后被动回复的文本，为空时不回复
+	ConfirmReply string
+}
+
+// NewQRLogin 实例化，expire 为二维码有效期，<=0 时使用 DefaultExpire，最长30天
+func NewQRLogin(context *context.Context, expire time.Duration) *QRLogin {
+	if expire <= 0 {
+		expire = DefaultExpire
+	}
+	return &QRLogin{
+		Context:      context,
+		appID:        context.AppID,
+		expire:       expire,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// SetAppID 设置登录会话所属的appID，第三方平台代公众号处理时应设置为授权方appID
+func (q *QRLogin) SetAppID(appID string) {
+	q.appID = appID
+}
+
+func (q *QRLogin) cacheKey(scene string) string {
+	return fmt.Sprintf("%s_qrlogin_%s_%s", credential.CacheKeyOfficialAccountPrefix, q.appID, scene)
+}
+
+// Create 创建登录会话并生成临时二维码，将 Session.Scene 保存到网页会话中用于轮询结果
+func (q *QRLogin) Create() (*Session, error) {
+	scene := ScenePrefix + util.RandomStr(32)
+	ticket, err := basic.NewBasic(q.Context).GetQRTicket(basic.NewTmpQrRequest(q.expire, scene))
+	if err != nil {
+		return nil, err
+	}
+	if ticket.ErrCode != 0 {
+		return nil, fmt.Errorf("GetQRTicket Error , errcode=%d , errmsg=%s", ticket.ErrCode, ticket.ErrMsg)
+	}
+	now := time.Now()
+	sess := &Session{
+		Scene:     scene,
+		Status:    StatusPending,
+		Ticket:    ticket.Ticket,
+		URL:       ticket.URL,
+		QRCodeURL: basic.ShowQRCode(ticket),
+		CreatedAt: now.Unix(),
+		ExpireAt:  now.Add(q.expire).Unix(),
+	}
+	if err = q.save(sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// save 会话在二维码过期后继续保留一个有效期，以便轮询方得到过期状态
+func (q *QRLogin) save(sess *Session) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(time.Unix(sess.ExpireAt, 0)) + q.expire
+	return q.Cache.Set(q.cacheKey(sess.Scene), string(data), ttl)
+}
+
+func (q *QRLogin) load(scene string) (*Session, error) {
+	val := q.Cache.Get(q.cacheKey(scene))
+	if val == nil {
+		return nil, ErrSessionNotFound
+	}
+	str, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("qrlogin session type error: %T", val)
+	}
+	sess := new(Session)
+	if err := json.Unmarshal([]byte(str), sess); err != nil {
+		return nil, fmt.Errorf("qrlogin session unmarshal error, err=%v", err)
+	}
+	return sess, nil
+}
+
+// ParseScene 从关注/扫码事件中解析登录scene，不是扫码登录二维码时返回false
+func ParseScene(msg message.MixMessage) (string, bool) {
+	if msg.MsgType != message.MsgTypeEvent {
+		return "", false
+	}
+	scene := msg.EventKey
+	switch msg.Event {
+	case message.EventSubscribe:
+		scene = strings.TrimPrefix(scene, subscribeScenePrefix)
+	case message.EventScan:
+	default:
+		return "", false
+	}
+	if !strings.HasPrefix(scene, ScenePrefix) {
+		return "", false
+	}
+	return scene, true
+}
+
+// HandleEvent 处理关注/扫码事件，将对应的登录会话标记为已扫码，不是扫码登录事件时返回 nil, nil
+func (q *QRLogin) HandleEvent(msg message.MixMessage) (*Session, error) {
+	scene, ok := ParseScene(msg)
+	if !ok {
+		return nil, nil
+	}
+	sess, err := q.load(scene)
+	if err != nil {
+		return nil, err
+	}
+	if sess.Status == StatusConfirmed {
+		return sess, nil
+	}
+	if sess.Expired() {
+		sess.Status = StatusExpired
+		return sess, nil
+	}
+	sess.Status = StatusConfirmed
+	sess.OpenID = string(msg.FromUserName)
+	sess.ConfirmedAt = time.Now().Unix()
+	if err = q.save(sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// Handler 包装消息处理方法，扫码登录事件由 HandleEvent 处理，其余消息交给next，可直接传给 server.SetMessageHandler
+func (q *QRLogin) Handler(next func(msg message.MixMessage) *message.Reply) func(msg message.MixMessage) *message.Reply {
+	return func(msg message.MixMessage) *message.Reply {
+		if _, ok := ParseScene(msg); !ok {
+			if next == nil {
+				return nil
+			}
+			return next(msg)
+		}
+		sess, err := q.HandleEvent(msg)
+		if err != nil {
+			log.Errorf("qrlogin handle event failed, openID=%s, err=%v", msg.FromUserName, err)
+			return nil
+		}
+		if sess.Status != StatusConfirmed || q.ConfirmReply == "" {
+			return nil
+		}
+		return &message.Reply{MsgType: message.MsgTypeText, MsgData: message.NewText(q.ConfirmReply)}
+	}
+}
+
+// Poll 查询登录结果，用户扫码后返回 StatusConfirmed 并清除会话，并发轮询同一会话时只有一方能得到 StatusConfirmed，
+// 其余返回 ErrSessionNotFound；多实例部署时需要cache实现 cache.Adder（内置的 Memory、Redis、Memcache 均已实现）
+func (q *QRLogin) Poll(scene string) (*Session, error) {
+	sess, err := q.load(scene)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case sess.Status == StatusConfirmed:
+		claimed, err := q.claim(sess)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			return nil, ErrSessionNotFound
+		}
+		if err = q.Cache.Delete(q.cacheKey(scene)); err != nil {
+			return nil, err
+		}
+	case sess.Expired():
+		sess.Status = StatusExpired
+	}
+	return sess, nil
+}
+
+// claim 标记会话的登录结果已被读取，返回是否由本次调用标记成功
+// cache 未实现 cache.Adder 时只能先判断再写入，仅在单个进程内加锁
+func (q *QRLogin) claim(sess *Session) (bool, error) {
+	cacheKey := q.cacheKey(sess.Scene) + "_consumed"
+	ttl := time.Until(time.Unix(sess.ExpireAt, 0)) + q.expire
+	if adder, ok := q.Cache.(cache.Adder); ok {
+		return adder.Add(cacheKey, "1", ttl)
+	}
+
+	claimLock.Lock()
+	defer claimLock.Unlock()
+	if q.Cache.IsExist(cacheKey) {
+		return false, nil
+	}
+	if err := q.Cache.Set(cacheKey, "1", ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Wait 阻塞等待登录结果，直到用户扫码、二维码过期或ctx结束
+func (q *QRLogin) Wait(ctx stdcontext.Context, scene string) (*Session, error) {
+	interval := q.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		sess, err := q.Poll(scene)
+		if err != nil {
+			return nil, err
+		}
+		if sess.Status != StatusPending {
+			return sess, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
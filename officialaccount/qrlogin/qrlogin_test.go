@@ -0,0 +1,113 @@
+package qrlogin
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/officialaccount/config"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestQRLogin() *QRLogin {
+	ctx := &context.Context{
+		Config: &config.Config{AppID: "wx123", Cache: cache.NewMemory()},
+	}
+	return NewQRLogin(ctx, time.Minute)
+}
+
+func eventMessage(event message.EventType, eventKey, openID string) message.MixMessage {
+	msg := message.MixMessage{Event: event, EventKey: eventKey}
+	msg.MsgType = message.MsgTypeEvent
+	msg.FromUserName = message.CDATA(openID)
+	return msg
+}
+
+func TestParseScene(t *testing.T) {
+	scene, ok := ParseScene(eventMessage(message.EventSubscribe, "qrscene_qrlogin_abc", "o1"))
+	assert.True(t, ok)
+	assert.Equal(t, "qrlogin_abc", scene)
+
+	scene, ok = ParseScene(eventMessage(message.EventScan, "qrlogin_abc", "o1"))
+	assert.True(t, ok)
+	assert.Equal(t, "qrlogin_abc", scene)
+
+	_, ok = ParseScene(eventMessage(message.EventScan, "other_scene", "o1"))
+	assert.False(t, ok)
+	_, ok = ParseScene(eventMessage(message.EventClick, "qrlogin_abc", "o1"))
+	assert.False(t, ok)
+}
+
+func TestHandleEventAndPoll(t *testing.T) {
+	q := newTestQRLogin()
+	now := time.Now()
+	sess := &Session{Scene: "qrlogin_abc", Status: StatusPending, CreatedAt: now.Unix(), ExpireAt: now.Add(time.Minute).Unix()}
+	assert.Nil(t, q.save(sess))
+
+	res, err := q.Poll(sess.Scene)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusPending, res.Status)
+
+	res, err = q.HandleEvent(eventMessage(message.EventScan, sess.Scene, "openid1"))
+	assert.Nil(t, err)
+	assert.Equal(t, StatusConfirmed, res.Status)
+
+	res, err = q.Poll(sess.Scene)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusConfirmed, res.Status)
+	assert.Equal(t, "openid1", res.OpenID)
+
+	//登录结果只能被读取一次
+	_, err = q.Poll(sess.Scene)
+	assert.Equal(t, ErrSessionNotFound, err)
+}
+
+func TestPollConcurrent(t *testing.T) {
+	q := newTestQRLogin()
+	now := time.Now()
+	sess := &Session{Scene: "qrlogin_concurrent", Status: StatusPending, CreatedAt: now.Unix(), ExpireAt: now.Add(time.Minute).Unix()}
+	assert.Nil(t, q.save(sess))
+	_, err := q.HandleEvent(eventMessage(message.EventScan, sess.Scene, "openid1"))
+	assert.Nil(t, err)
+
+	var (
+		wg        sync.WaitGroup
+		lock      sync.Mutex
+		confirmed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := q.Poll(sess.Scene)
+			if err != nil {
+				assert.Equal(t, ErrSessionNotFound, err)
+				return
+			}
+			if res.Status == StatusConfirmed {
+				lock.Lock()
+				confirmed++
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, confirmed)
+}
+
+func TestPollExpired(t *testing.T) {
+	q := newTestQRLogin()
+	sess := &Session{Scene: "qrlogin_expired", Status: StatusPending, ExpireAt: time.Now().Add(-time.Second).Unix()}
+	assert.Nil(t, q.save(sess))
+
+	res, err := q.Poll(sess.Scene)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusExpired, res.Status)
+
+	res, err = q.HandleEvent(eventMessage(message.EventScan, sess.Scene, "openid1"))
+	assert.Nil(t, err)
+	assert.Equal(t, StatusExpired, res.Status)
+}
@@ -9,7 +9,9 @@ import (
 	"reflect"
 	"runtime/debug"
 	"strconv"
+	"time"
 
+	"github.com/silenceper/wechat/v2/officialaccount/audit"
 	"github.com/silenceper/wechat/v2/officialaccount/context"
 	"github.com/silenceper/wechat/v2/officialaccount/message"
 	log "github.com/sirupsen/logrus"
@@ -23,7 +25,9 @@ type Server struct {
 	Writer  http.ResponseWriter
 	Request *http.Request
 
-	skipValidate bool
+	skipValidate  bool
+	replayChecker *util.ReplayChecker
+	auditSink     audit.Sink
 
 	openID string
 
@@ -38,6 +42,10 @@ type Server struct {
 	random     []byte
 	nonce      string
 	timestamp  int64
+	aesKey     string
+	aesKeyIdx  int
+
+	handleCost time.Duration
 }
 
 //NewServer init
@@ -52,12 +60,27 @@ func (srv *Server) SkipValidate(skip bool) {
 	srv.skipValidate = skip
 }
 
+// SetReplayChecker 设置防重放校验，校验timestamp新鲜度以及nonce是否重复
+// checker 应在多个请求间复用，以便共享nonce记录与统计
+func (srv *Server) SetReplayChecker(checker *util.ReplayChecker) {
+	srv.replayChecker = checker
+}
+
+// SetAuditSink 设置消息审计，每次处理完消息后将请求、回复、耗时及错误写入sink
+func (srv *Server) SetAuditSink(sink audit.Sink) {
+	srv.auditSink = sink
+}
+
 //Serve 处理微信的请求消息
 func (srv *Server) Serve() error {
 	if !srv.Validate() {
 		log.Error("Validate Signature Failed.")
 		return fmt.Errorf("请求校验失败")
 	}
+	if err := srv.checkReplay(); err != nil {
+		log.Errorf("Replay Check Failed, err=%v", err)
+		return err
+	}
 
 	echostr, exists := srv.GetQuery("echostr")
 	if exists {
@@ -65,15 +88,45 @@ func (srv *Server) Serve() error {
 		return nil
 	}
 
+	startTime := time.Now()
 	response, err := srv.handleRequest()
 	if err != nil {
+		srv.audit(startTime, err)
 		return err
 	}
 
 	//debug print request msg
 	log.Debugf("request msg =%s", string(srv.RequestRawXMLMsg))
 
-	return srv.buildResponse(response)
+	err = srv.buildResponse(response)
+	srv.audit(startTime, err)
+	return err
+}
+
+//audit 写入审计记录
+func (srv *Server) audit(startTime time.Time, err error) {
+	if srv.auditSink == nil {
+		return
+	}
+	record := &audit.Record{
+		AppID:       srv.AppID,
+		OpenID:      string(srv.RequestMsg.FromUserName),
+		MsgType:     string(srv.RequestMsg.MsgType),
+		Event:       string(srv.RequestMsg.Event),
+		MsgID:       srv.RequestMsg.MsgID,
+		Content:     srv.RequestMsg.Content,
+		RequestXML:  string(srv.RequestRawXMLMsg),
+		ResponseXML: string(srv.ResponseRawXMLMsg),
+		ReceivedAt:  startTime,
+		HandleCost:  srv.handleCost,
+		TotalCost:   time.Since(startTime),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if e := srv.auditSink.Write(record); e != nil {
+		log.Errorf("write audit record failed, err=%v", e)
+	}
 }
 
 //Validate 校验请求是否合法
@@ -88,6 +141,14 @@ func (srv *Server) Validate() bool {
 	return signature == util.Signature(srv.Token, timestamp, nonce)
 }
 
+//checkReplay 防重放校验
+func (srv *Server) checkReplay() error {
+	if srv.skipValidate || srv.replayChecker == nil {
+		return nil
+	}
+	return srv.replayChecker.Check(srv.Query("timestamp"), srv.Query("nonce"))
+}
+
 //HandleRequest 处理微信的请求
 func (srv *Server) handleRequest() (reply *message.Reply, err error) {
 	//set isSafeMode
@@ -110,7 +171,9 @@ func (srv *Server) handleRequest() (reply *message.Reply, err error) {
 		err = errors.New("消息类型转换失败")
 	}
 	srv.RequestMsg = mixMessage
+	handleStart := time.Now()
 	reply = srv.messageHandler(mixMessage)
+	srv.handleCost = time.Since(handleStart)
 	return
 }
 
@@ -119,6 +182,15 @@ func (srv *Server) GetOpenID() string {
 	return srv.openID
 }
 
+//GetEncodingAESKeyIndex 返回解密当前请求所使用的EncodingAESKey序号，0 为当前的 EncodingAESKey，
+//n 为 PrevEncodingAESKeys[n-1]，非安全模式下为 -1；可用于确认轮换后是否仍有请求使用历史key
+func (srv *Server) GetEncodingAESKeyIndex() int {
+	if srv.aesKey == "" {
+		return -1
+	}
+	return srv.aesKeyIdx
+}
+
 //getMessage 解析微信返回的消息
 func (srv *Server) getMessage() (interface{}, error) {
 	var rawXMLMsgBytes []byte
@@ -143,11 +215,21 @@ func (srv *Server) getMessage() (interface{}, error) {
 			return nil, fmt.Errorf("消息不合法，验证签名失败")
 		}
 
-		//解密
-		srv.random, rawXMLMsgBytes, err = util.DecryptMsg(srv.AppID, encryptedXMLMsg.EncryptedMsg, srv.EncodingAESKey)
+		//解密，EncodingAESKey轮换期间依次尝试历史key
+		aesKeys := append([]string{srv.EncodingAESKey}, srv.PrevEncodingAESKeys...)
+		srv.random, rawXMLMsgBytes, srv.aesKey, err = util.DecryptMsgWithKeys(srv.AppID, encryptedXMLMsg.EncryptedMsg, aesKeys)
 		if err != nil {
 			return nil, fmt.Errorf("消息解密失败, err=%v", err)
 		}
+		for i, key := range aesKeys {
+			if key == srv.aesKey {
+				srv.aesKeyIdx = i
+				break
+			}
+		}
+		if srv.aesKeyIdx > 0 {
+			log.Warnf("message decrypted with previous EncodingAESKey, appID=%s, index=%d", srv.AppID, srv.aesKeyIdx)
+		}
 	} else {
 		rawXMLMsgBytes, err = ioutil.ReadAll(srv.Request.Body)
 		if err != nil {
@@ -228,7 +310,12 @@ func (srv *Server) Send() (err error) {
 	if srv.isSafeMode {
 		//安全模式下对消息进行加密
 		var encryptedMsg []byte
-		encryptedMsg, err = util.EncryptMsg(srv.random, srv.ResponseRawXMLMsg, srv.AppID, srv.EncodingAESKey)
+		//使用解密请求时的key加密回复
+		aesKey := srv.aesKey
+		if aesKey == "" {
+			aesKey = srv.EncodingAESKey
+		}
+		encryptedMsg, err = util.EncryptMsg(srv.random, srv.ResponseRawXMLMsg, srv.AppID, aesKey)
 		if err != nil {
 			return
 		}
@@ -0,0 +1,190 @@
+package subscribe
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/credential"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	//一次性订阅消息
+	//https://developers.weixin.qq.com/doc/offiaccount/Message_Management/One-time_subscription_info.html
+	onceAuthURL = "https://mp.weixin.qq.com/mp/subscribemsg?action=get_confirm&appid=%s&scene=%d&template_id=%s&redirect_url=%s&reserved=%s#wechat_redirect"
+	onceSendURL = "https://api.weixin.qq.com/cgi-bin/message/template/subscribe"
+)
+
+const (
+	// OnceActionConfirm 用户同意授权
+	OnceActionConfirm = "confirm"
+	// OnceActionCancel 用户取消授权
+	OnceActionCancel = "cancel"
+
+	// OnceSceneMax 一次性订阅消息 scene 的最大值，scene 取值范围为0-10000
+	OnceSceneMax = 10000
+)
+
+// ErrInvalidReserved reserved 校验失败，可能是伪造或过期的回调
+var ErrInvalidReserved = errors.New("subscribemsg reserved is invalid or expired")
+
+// consumeLock cache 未实现 cache.Adder 时用于标记reserved已被使用，GetSubscribe 每次返回新实例，因此使用包级别的锁
+var consumeLock sync.Mutex
+
+// OnceAuthResult 用户授权后跳转回 redirect_url 时携带的结果
+type OnceAuthResult struct {
+	OpenID     string
+	TemplateID string
+	Action     string // confirm 表示用户同意授权，cancel 表示用户取消授权
+	Scene      int
+	Reserved   string
+}
+
+// Confirmed 用户是否同意授权
+func (res *OnceAuthResult) Confirmed() bool {
+	return res.Action == OnceActionConfirm
+}
+
+// OnceMessage 一次性订阅消息
+type OnceMessage struct {
+	ToUser      string                   `json:"touser"`
+	TemplateID  string                   `json:"template_id"`
+	URL         string                   `json:"url,omitempty"`
+	MiniProgram *MiniProgram             `json:"miniprogram,omitempty"`
+	Scene       string                   `json:"scene"` // 订阅场景值，需与授权时的scene一致
+	Title       string                   `json:"title"` // 消息标题，15字以内
+	Data        map[string]*OnceDataItem `json:"data"`  // 消息正文，目前仅支持 content
+}
+
+// OnceDataItem 一次性订阅消息内容
+type OnceDataItem struct {
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+// BuildOnceAuthURL 构造一次性订阅消息的授权页地址
+// scene 为0-10000的整形值，reserved 用于保持请求和回调的状态，最长128字节
+func (s *Subscribe) BuildOnceAuthURL(templateID, redirectURL string, scene int, reserved string) (string, error) {
+	if err := validateOnceScene(scene); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(onceAuthURL, s.AppID, scene, url.QueryEscape(templateID), url.QueryEscape(redirectURL), url.QueryEscape(reserved)), nil
+}
+
+// GetOnceAuthURL 构造一次性订阅消息的授权页地址，并生成随机reserved作为防CSRF令牌保存在cache中
+func (s *Subscribe) GetOnceAuthURL(templateID, redirectURL string, scene int, expire time.Duration) (string, error) {
+	reserved := util.RandomStr(32)
+	authURL, err := s.BuildOnceAuthURL(templateID, redirectURL, scene, reserved)
+	if err != nil {
+		return "", err
+	}
+	val := fmt.Sprintf("%s:%d", onceReservedValue(templateID, scene), time.Now().Add(expire).Unix())
+	if err = s.Cache.Set(s.reservedCacheKey(reserved), val, expire); err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// ParseOnceAuthRedirect 解析授权后的回调，并校验reserved是否为 GetOnceAuthURL 生成且未被使用
+func (s *Subscribe) ParseOnceAuthRedirect(req *http.Request) (*OnceAuthResult, error) {
+	query := req.URL.Query()
+	res := &OnceAuthResult{
+		OpenID:     query.Get("openid"),
+		TemplateID: query.Get("template_id"),
+		Action:     query.Get("action"),
+		Reserved:   query.Get("reserved"),
+	}
+	var err error
+	if res.Scene, err = strconv.Atoi(query.Get("scene")); err != nil {
+		return nil, fmt.Errorf("subscribemsg scene is invalid: %s", query.Get("scene"))
+	}
+	if err = validateOnceScene(res.Scene); err != nil {
+		return nil, err
+	}
+	if res.Reserved == "" {
+		return nil, ErrInvalidReserved
+	}
+	cacheKey := s.reservedCacheKey(res.Reserved)
+	val, ok := s.Cache.Get(cacheKey).(string)
+	if !ok {
+		return nil, ErrInvalidReserved
+	}
+	sep := strings.LastIndex(val, ":")
+	if sep < 0 || val[:sep] != onceReservedValue(res.TemplateID, res.Scene) {
+		return nil, ErrInvalidReserved
+	}
+	expireAt, err := strconv.ParseInt(val[sep+1:], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidReserved
+	}
+	//reserved 只能使用一次，并发回调时只有一方能通过校验
+	consumed, err := s.consumeReserved(cacheKey, time.Until(time.Unix(expireAt, 0)))
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidReserved
+	}
+	if err = s.Cache.Delete(cacheKey); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// consumeReserved 标记reserved已被使用，返回是否由本次调用标记成功
+// cache 实现了 cache.Adder 时（内置的 Memory、Redis、Memcache 均已实现）使用原子写入，否则仅在单个进程内加锁
+func (s *Subscribe) consumeReserved(cacheKey string, ttl time.Duration) (bool, error) {
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	consumedKey := cacheKey + "_consumed"
+	if adder, ok := s.Cache.(cache.Adder); ok {
+		return adder.Add(consumedKey, "1", ttl)
+	}
+
+	consumeLock.Lock()
+	defer consumeLock.Unlock()
+	if s.Cache.IsExist(consumedKey) {
+		return false, nil
+	}
+	if err := s.Cache.Set(consumedKey, "1", ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SendOnce 通过API推送订阅模板消息给到授权微信用户
+func (s *Subscribe) SendOnce(msg *OnceMessage) error {
+	accessToken, err := s.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", onceSendURL, accessToken)
+	response, err := util.PostJSON(uri, msg)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithCommonError(response, "SendOnce")
+}
+
+func (s *Subscribe) reservedCacheKey(reserved string) string {
+	return fmt.Sprintf("%s_subscribemsg_reserved_%s_%s", credential.CacheKeyOfficialAccountPrefix, s.AppID, reserved)
+}
+
+func validateOnceScene(scene int) error {
+	if scene < 0 || scene > OnceSceneMax {
+		return fmt.Errorf("subscribemsg scene must be between 0 and %d, got %d", OnceSceneMax, scene)
+	}
+	return nil
+}
+
+func onceReservedValue(templateID string, scene int) string {
+	return fmt.Sprintf("%s:%d", templateID, scene)
+}
@@ -0,0 +1,145 @@
+package subscribe
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/silenceper/wechat/v2/officialaccount/config"
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+func newTestSubscribe() *Subscribe {
+	return NewSubscribe(&context.Context{
+		Config:            &config.Config{AppID: "wx123", Cache: cache.NewMemory()},
+		AccessTokenHandle: mockAccessToken{},
+	})
+}
+
+// onceRedirect 模拟用户授权后跳转回 redirect_url 的请求
+func onceRedirect(t *testing.T, authURL, openID string) *http.Request {
+	u, err := url.Parse(authURL)
+	assert.Nil(t, err)
+	query := u.Query()
+	redirect := url.Values{}
+	redirect.Set("openid", openID)
+	redirect.Set("template_id", query.Get("template_id"))
+	redirect.Set("action", OnceActionConfirm)
+	redirect.Set("scene", query.Get("scene"))
+	redirect.Set("reserved", query.Get("reserved"))
+	return httptest.NewRequest(http.MethodGet, "/subscribe/callback?"+redirect.Encode(), nil)
+}
+
+func TestBuildOnceAuthURLScene(t *testing.T) {
+	s := newTestSubscribe()
+	_, err := s.BuildOnceAuthURL("tpl", "https://example.com/cb", OnceSceneMax, "r")
+	assert.Nil(t, err)
+	_, err = s.BuildOnceAuthURL("tpl", "https://example.com/cb", OnceSceneMax+1, "r")
+	assert.NotNil(t, err)
+	_, err = s.BuildOnceAuthURL("tpl", "https://example.com/cb", -1, "r")
+	assert.NotNil(t, err)
+	_, err = s.GetOnceAuthURL("tpl", "https://example.com/cb", OnceSceneMax+1, time.Minute)
+	assert.NotNil(t, err)
+}
+
+func TestParseOnceAuthRedirect(t *testing.T) {
+	s := newTestSubscribe()
+	authURL, err := s.GetOnceAuthURL("tpl", "https://example.com/cb", 100, time.Minute)
+	assert.Nil(t, err)
+
+	req := onceRedirect(t, authURL, "openid1")
+	res, err := s.ParseOnceAuthRedirect(req)
+	assert.Nil(t, err)
+	assert.True(t, res.Confirmed())
+	assert.Equal(t, "openid1", res.OpenID)
+	assert.Equal(t, 100, res.Scene)
+
+	//reserved 只能使用一次
+	_, err = s.ParseOnceAuthRedirect(req)
+	assert.Equal(t, ErrInvalidReserved, err)
+
+	//scene 与授权时不一致
+	authURL, err = s.GetOnceAuthURL("tpl", "https://example.com/cb", 100, time.Minute)
+	assert.Nil(t, err)
+	req = onceRedirect(t, authURL, "openid1")
+	query := req.URL.Query()
+	query.Set("scene", "101")
+	req.URL.RawQuery = query.Encode()
+	_, err = s.ParseOnceAuthRedirect(req)
+	assert.Equal(t, ErrInvalidReserved, err)
+
+	//scene 超出范围
+	query.Set("scene", "10001")
+	req.URL.RawQuery = query.Encode()
+	_, err = s.ParseOnceAuthRedirect(req)
+	assert.NotNil(t, err)
+	assert.NotEqual(t, ErrInvalidReserved, err)
+}
+
+func TestParseOnceAuthRedirectConcurrent(t *testing.T) {
+	s := newTestSubscribe()
+	authURL, err := s.GetOnceAuthURL("tpl", "https://example.com/cb", 1, time.Minute)
+	assert.Nil(t, err)
+
+	var (
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		passed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.ParseOnceAuthRedirect(onceRedirect(t, authURL, "openid1")); err == nil {
+				lock.Lock()
+				passed++
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, passed)
+}
+
+func TestSendOnce(t *testing.T) {
+	defer gock.Off()
+	gock.New(onceSendURL).
+		MatchParam("access_token", "mock-ak").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return false, err
+			}
+			var msg OnceMessage
+			if err = json.Unmarshal(body, &msg); err != nil {
+				return false, err
+			}
+			return msg.ToUser == "openid1" && msg.Scene == "100" && msg.Data["content"].Value == "hi", nil
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	err := newTestSubscribe().SendOnce(&OnceMessage{
+		ToUser:     "openid1",
+		TemplateID: "tpl",
+		Scene:      "100",
+		Title:      "title",
+		Data:       map[string]*OnceDataItem{"content": {Value: "hi"}},
+	})
+	assert.Nil(t, err)
+	assert.True(t, gock.IsDone())
+}
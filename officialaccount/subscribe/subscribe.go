@@ -0,0 +1,237 @@
+package subscribe
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	//公众号订阅通知
+	//https://developers.weixin.qq.com/doc/offiaccount/Subscription_Messages/api.html
+	getCategoryURL            = "https://api.weixin.qq.com/wxaapi/newtmpl/getcategory"
+	getPubTemplateTitlesURL   = "https://api.weixin.qq.com/wxaapi/newtmpl/getpubtemplatetitles"
+	getPubTemplateKeywordsURL = "https://api.weixin.qq.com/wxaapi/newtmpl/getpubtemplatekeywords"
+	addTemplateURL            = "https://api.weixin.qq.com/wxaapi/newtmpl/addtemplate"
+	delTemplateURL            = "https://api.weixin.qq.com/wxaapi/newtmpl/deltemplate"
+	getTemplateURL            = "https://api.weixin.qq.com/wxaapi/newtmpl/gettemplate"
+	bizSendURL                = "https://api.weixin.qq.com/cgi-bin/message/subscribe/bizsend"
+)
+
+// Subscribe 订阅通知
+type Subscribe struct {
+	*context.Context
+}
+
+// NewSubscribe 实例化
+func NewSubscribe(ctx *context.Context) *Subscribe {
+	return &Subscribe{Context: ctx}
+}
+
+// Category 公众号所属类目
+type Category struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type resCategory struct {
+	util.CommonError
+
+	Data []*Category `json:"data"`
+}
+
+// PubTemplateTitle 类目下的公共模板
+type PubTemplateTitle struct {
+	TID        int64  `json:"tid"`
+	Title      string `json:"title"`
+	Type       int    `json:"type"` // 模板类型，2 为一次性订阅，3 为长期订阅
+	CategoryID string `json:"categoryId"`
+}
+
+// PubTemplateTitleList 类目下的公共模板列表
+type PubTemplateTitleList struct {
+	util.CommonError
+
+	Count int                 `json:"count"`
+	Data  []*PubTemplateTitle `json:"data"`
+}
+
+// PubTemplateKeyword 公共模板的关键词
+type PubTemplateKeyword struct {
+	KID     int64  `json:"kid"`
+	Name    string `json:"name"`
+	Example string `json:"example"`
+	Rule    string `json:"rule"`
+}
+
+type resPubTemplateKeywords struct {
+	util.CommonError
+
+	Count int                   `json:"count"`
+	Data  []*PubTemplateKeyword `json:"data"`
+}
+
+// TemplateItem 私有模板
+type TemplateItem struct {
+	PriTmplID string `json:"priTmplId"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Example   string `json:"example"`
+	Type      int    `json:"type"`
+}
+
+type resTemplateList struct {
+	util.CommonError
+
+	Data []*TemplateItem `json:"data"`
+}
+
+type resAddTemplate struct {
+	util.CommonError
+
+	PriTmplID string `json:"priTmplId"`
+}
+
+// Message 订阅通知请求参数
+type Message struct {
+	ToUser      string               `json:"touser"`                // 必选，接收者（用户）的 openid
+	TemplateID  string               `json:"template_id"`           // 必选，所需下发的订阅模板id
+	Page        string               `json:"page,omitempty"`        // 可选，跳转网页时填写
+	MiniProgram *MiniProgram         `json:"miniprogram,omitempty"` // 可选，跳转小程序时填写，page 和 miniprogram 同时填写时优先跳转小程序
+	Data        map[string]*DataItem `json:"data"`                  // 必选，模板内容
+}
+
+// MiniProgram 跳转的小程序
+type MiniProgram struct {
+	AppID    string `json:"appid"`
+	PagePath string `json:"pagepath"`
+}
+
+// DataItem 模版内某个 .DATA 的值
+type DataItem struct {
+	Value string `json:"value"`
+}
+
+// GetCategory 获取公众号所属类目
+func (s *Subscribe) GetCategory() ([]*Category, error) {
+	accessToken, err := s.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", getCategoryURL, accessToken)
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	res := &resCategory{}
+	err = util.DecodeWithError(response, res, "GetCategory")
+	return res.Data, err
+}
+
+// GetPubTemplateTitles 获取类目下的公共模板，ids 为类目id，多个用逗号隔开，limit 最大为30
+func (s *Subscribe) GetPubTemplateTitles(ids string, start, limit int) (*PubTemplateTitleList, error) {
+	accessToken, err := s.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("access_token", accessToken)
+	params.Set("ids", ids)
+	params.Set("start", strconv.Itoa(start))
+	params.Set("limit", strconv.Itoa(limit))
+	response, err := util.HTTPGet(getPubTemplateTitlesURL + "?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	res := &PubTemplateTitleList{}
+	err = util.DecodeWithError(response, res, "GetPubTemplateTitles")
+	return res, err
+}
+
+// GetPubTemplateKeywords 获取公共模板下的关键词列表
+func (s *Subscribe) GetPubTemplateKeywords(tid string) ([]*PubTemplateKeyword, error) {
+	accessToken, err := s.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s&tid=%s", getPubTemplateKeywordsURL, accessToken, url.QueryEscape(tid))
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	res := &resPubTemplateKeywords{}
+	err = util.DecodeWithError(response, res, "GetPubTemplateKeywords")
+	return res.Data, err
+}
+
+// AddTemplate 从公共模板库中选用模板，kidList 为关键词id列表，sceneDesc 为服务场景描述
+func (s *Subscribe) AddTemplate(tid string, kidList []int64, sceneDesc string) (priTmplID string, err error) {
+	var accessToken string
+	accessToken, err = s.GetAccessToken()
+	if err != nil {
+		return
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", addTemplateURL, accessToken)
+	var response []byte
+	response, err = util.PostJSON(uri, map[string]interface{}{
+		"tid":       tid,
+		"kidList":   kidList,
+		"sceneDesc": sceneDesc,
+	})
+	if err != nil {
+		return
+	}
+	res := &resAddTemplate{}
+	err = util.DecodeWithError(response, res, "AddTemplate")
+	priTmplID = res.PriTmplID
+	return
+}
+
+// DeleteTemplate 删除私有模板
+func (s *Subscribe) DeleteTemplate(priTmplID string) error {
+	accessToken, err := s.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", delTemplateURL, accessToken)
+	response, err := util.PostJSON(uri, map[string]string{
+		"priTmplId": priTmplID,
+	})
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithCommonError(response, "DeleteTemplate")
+}
+
+// ListTemplate 获取私有模板列表
+func (s *Subscribe) ListTemplate() ([]*TemplateItem, error) {
+	accessToken, err := s.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", getTemplateURL, accessToken)
+	response, err := util.HTTPGet(uri)
+	if err != nil {
+		return nil, err
+	}
+	res := &resTemplateList{}
+	err = util.DecodeWithError(response, res, "ListTemplate")
+	return res.Data, err
+}
+
+// Send 发送订阅通知
+func (s *Subscribe) Send(msg *Message) error {
+	accessToken, err := s.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	uri := fmt.Sprintf("%s?access_token=%s", bizSendURL, accessToken)
+	response, err := util.PostJSON(uri, msg)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithCommonError(response, "Send")
+}
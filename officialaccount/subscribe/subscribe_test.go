@@ -0,0 +1,92 @@
+package subscribe
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// matchBody 校验请求体的JSON内容
+func matchBody(t *testing.T, expected string) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		var got, want interface{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal([]byte(expected), &want); err != nil {
+			return false, err
+		}
+		return assert.Equal(t, want, got), nil
+	}
+}
+
+func TestPubTemplates(t *testing.T) {
+	defer gock.Off()
+	gock.New(getCategoryURL).MatchParam("access_token", "mock-ak").
+		Reply(200).JSON(map[string]interface{}{"data": []map[string]interface{}{{"id": 616, "name": "公交"}}})
+	gock.New(getPubTemplateTitlesURL).
+		MatchParams(map[string]string{"access_token": "mock-ak", "ids": "616,617", "start": "0", "limit": "30"}).
+		Reply(200).JSON(map[string]interface{}{
+		"count": 1,
+		"data":  []map[string]interface{}{{"tid": 99, "title": "付款成功通知", "type": 2, "categoryId": "616"}},
+	})
+	gock.New(getPubTemplateKeywordsURL).MatchParam("tid", "99").
+		Reply(200).JSON(map[string]interface{}{"count": 1, "data": []map[string]interface{}{{"kid": 1, "name": "物品名称", "rule": "thing"}}})
+
+	s := newTestSubscribe()
+	categories, err := s.GetCategory()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(616), categories[0].ID)
+
+	titles, err := s.GetPubTemplateTitles("616,617", 0, 30)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, titles.Count)
+	assert.Equal(t, int64(99), titles.Data[0].TID)
+
+	keywords, err := s.GetPubTemplateKeywords("99")
+	assert.Nil(t, err)
+	assert.Equal(t, "thing", keywords[0].Rule)
+	assert.True(t, gock.IsDone())
+}
+
+func TestPrivateTemplates(t *testing.T) {
+	defer gock.Off()
+	gock.New(addTemplateURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"tid":"99","kidList":[1,2],"sceneDesc":"付款通知"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok", "priTmplId": "tpl1"})
+	gock.New(getTemplateURL).
+		Reply(200).JSON(map[string]interface{}{"data": []map[string]interface{}{{"priTmplId": "tpl1", "title": "付款成功通知", "type": 3}}})
+	gock.New(delTemplateURL).AddMatcher(matchBody(t, `{"priTmplId":"tpl1"}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 20001, "errmsg": "system error"})
+
+	s := newTestSubscribe()
+	priTmplID, err := s.AddTemplate("99", []int64{1, 2}, "付款通知")
+	assert.Nil(t, err)
+	assert.Equal(t, "tpl1", priTmplID)
+
+	list, err := s.ListTemplate()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, list[0].Type)
+
+	assert.NotNil(t, s.DeleteTemplate("tpl1"))
+	assert.True(t, gock.IsDone())
+}
+
+func TestSend(t *testing.T) {
+	defer gock.Off()
+	gock.New(bizSendURL).MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"touser":"openid1","template_id":"tpl1","page":"https://example.com","data":{"thing1":{"value":"咖啡"}}}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	err := newTestSubscribe().Send(&Message{
+		ToUser:     "openid1",
+		TemplateID: "tpl1",
+		Page:       "https://example.com",
+		Data:       map[string]*DataItem{"thing1": {Value: "咖啡"}},
+	})
+	assert.Nil(t, err)
+	assert.True(t, gock.IsDone())
+}
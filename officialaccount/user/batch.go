@@ -0,0 +1,135 @@
+package user
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const batchGetUserInfoURL = "https://api.weixin.qq.com/cgi-bin/user/info/batchget?access_token=%s"
+
+const (
+	// batchGetUserInfoLimit 批量获取用户信息时每次最多的openid数量
+	batchGetUserInfoLimit = 100
+	// DefaultBatchConcurrency 批量获取用户信息的默认并发数
+	DefaultBatchConcurrency = 4
+)
+
+// StreamProgress 拉取关注者信息的进度
+type StreamProgress struct {
+	Total      int    // 关注者总数
+	Fetched    int    // 已拉取的用户信息数量
+	Checkpoint string // 已处理的最后一个openid，可作为 StreamUserInfo 的 startOpenID 从此处继续
+}
+
+// BatchGetUserInfo 批量获取用户基本信息，超过100个openid时自动分批，concurrency 为同时进行的请求数
+// 各批次结果按批次顺序合并，批次内的顺序以接口返回为准，需要对应时请使用 Info.OpenID
+func (user *User) BatchGetUserInfo(openIDs []string, concurrency int) ([]*Info, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	chunks := (len(openIDs) + batchGetUserInfoLimit - 1) / batchGetUserInfoLimit
+	results := make([][]*Info, chunks)
+	errs := make([]error, chunks)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < chunks; i++ {
+		start := i * batchGetUserInfoLimit
+		end := start + batchGetUserInfoLimit
+		if end > len(openIDs) {
+			end = len(openIDs)
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ids []string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i], errs[i] = user.batchGetUserInfo(ids)
+		}(i, openIDs[start:end])
+	}
+	wg.Wait()
+
+	infos := make([]*Info, 0, len(openIDs))
+	for i := range results {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("%v, openid offset=%d", errs[i], i*batchGetUserInfoLimit)
+		}
+		infos = append(infos, results[i]...)
+	}
+	return infos, nil
+}
+
+func (user *User) batchGetUserInfo(openIDs []string) ([]*Info, error) {
+	accessToken, err := user.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	type userItem struct {
+		OpenID string `json:"openid"`
+		Lang   string `json:"lang"`
+	}
+	userList := make([]userItem, 0, len(openIDs))
+	for _, openID := range openIDs {
+		userList = append(userList, userItem{OpenID: openID, Lang: "zh_CN"})
+	}
+	response, err := util.PostJSON(fmt.Sprintf(batchGetUserInfoURL, accessToken), map[string]interface{}{
+		"user_list": userList,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var res struct {
+		util.CommonError
+		UserInfoList []*Info `json:"user_info_list"`
+	}
+	if err = util.DecodeWithError(response, &res, "BatchGetUserInfo"); err != nil {
+		return nil, err
+	}
+	return res.UserInfoList, nil
+}
+
+// StreamUserInfo 逐批拉取全部关注者的基本信息，每批最多 100*concurrency 个用户
+// startOpenID 为空时从头开始，出错中断后可使用最后一次回调中的 progress.Checkpoint 继续；
+// fn 返回错误时停止拉取并返回该错误
+func (user *User) StreamUserInfo(startOpenID string, concurrency int, fn func(infos []*Info, progress StreamProgress) error) error {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	batchSize := batchGetUserInfoLimit * concurrency
+	it := user.IterateUserOpenIDs(startOpenID)
+	progress := StreamProgress{Checkpoint: startOpenID}
+	openIDs := make([]string, 0, batchSize)
+
+	flush := func() error {
+		if len(openIDs) == 0 {
+			return nil
+		}
+		infos, err := user.BatchGetUserInfo(openIDs, concurrency)
+		if err != nil {
+			return err
+		}
+		progress.Total = it.Total()
+		progress.Fetched += len(infos)
+		progress.Checkpoint = openIDs[len(openIDs)-1]
+		openIDs = openIDs[:0]
+		return fn(infos, progress)
+	}
+
+	for it.Next() {
+		openIDs = append(openIDs, it.OpenID())
+		if len(openIDs) < batchSize {
+			continue
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
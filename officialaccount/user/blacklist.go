@@ -0,0 +1,85 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	getBlackListURL     = "https://api.weixin.qq.com/cgi-bin/tags/members/getblacklist?access_token=%s"
+	batchBlackListURL   = "https://api.weixin.qq.com/cgi-bin/tags/members/batchblacklist?access_token=%s"
+	batchUnBlackListURL = "https://api.weixin.qq.com/cgi-bin/tags/members/batchunblacklist?access_token=%s"
+)
+
+// batchBlackListLimit 批量拉黑/取消拉黑时每次最多的openid数量
+const batchBlackListLimit = 20
+
+// GetBlackList 获取公众号的黑名单列表，每次最多拉取10000个，beginOpenID 为空时从头开始拉取
+func (user *User) GetBlackList(beginOpenID ...string) (*OpenidList, error) {
+	req := map[string]string{
+		"begin_openid": "",
+	}
+	if len(beginOpenID) > 0 {
+		req["begin_openid"] = beginOpenID[0]
+	}
+	var res struct {
+		util.CommonError
+		OpenidList
+	}
+	if err := user.tagRequest(getBlackListURL, req, &res, "GetBlackList"); err != nil {
+		return nil, err
+	}
+	return &res.OpenidList, nil
+}
+
+// GetAllBlackList 获取公众号的全部黑名单openid
+func (user *User) GetAllBlackList() ([]string, error) {
+	openIDs := make([]string, 0)
+	it := user.IterateBlackList("")
+	for it.Next() {
+		openIDs = append(openIDs, it.OpenID())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return openIDs, nil
+}
+
+// IterateBlackList 逐个迭代黑名单openid，beginOpenID 为空时从头开始
+func (user *User) IterateBlackList(beginOpenID string) *OpenIDIterator {
+	return newOpenIDIterator(beginOpenID, func(next string) ([]string, string, int, error) {
+		list, err := user.GetBlackList(next)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		return list.Data.OpenIDs, list.NextOpenID, list.Total, nil
+	})
+}
+
+// BatchBlackList 拉黑用户，openIDs 超过20个时自动分批请求
+func (user *User) BatchBlackList(openIDs ...string) error {
+	return user.batchBlackList(batchBlackListURL, openIDs, "BatchBlackList")
+}
+
+// BatchUnBlackList 取消拉黑用户，openIDs 超过20个时自动分批请求
+func (user *User) BatchUnBlackList(openIDs ...string) error {
+	return user.batchBlackList(batchUnBlackListURL, openIDs, "BatchUnBlackList")
+}
+
+func (user *User) batchBlackList(apiURL string, openIDs []string, apiName string) error {
+	for start := 0; start < len(openIDs); start += batchBlackListLimit {
+		end := start + batchBlackListLimit
+		if end > len(openIDs) {
+			end = len(openIDs)
+		}
+		req := map[string][]string{
+			"openid_list": openIDs[start:end],
+		}
+		var res struct{ util.CommonError }
+		if err := user.tagRequest(apiURL, req, &res, apiName); err != nil {
+			return fmt.Errorf("%v, openid offset=%d", err, start)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestGetAllBlackList(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/getblacklist").MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"begin_openid":""}`)).
+		Reply(200).JSON(map[string]interface{}{
+		"total": 3, "count": 2, "data": map[string]interface{}{"openid": []string{"a", "b"}}, "next_openid": "b",
+	})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/getblacklist").
+		AddMatcher(matchBody(t, `{"begin_openid":"b"}`)).
+		Reply(200).JSON(map[string]interface{}{
+		"total": 3, "count": 1, "data": map[string]interface{}{"openid": []string{"c"}}, "next_openid": "c",
+	})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/getblacklist").
+		AddMatcher(matchBody(t, `{"begin_openid":"c"}`)).
+		Reply(200).JSON(map[string]interface{}{"total": 3, "count": 0, "next_openid": ""})
+
+	openIDs, err := newTestUser().GetAllBlackList()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, openIDs)
+	assert.True(t, gock.IsDone())
+}
+
+func TestGetBlackListError(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/getblacklist").
+		Reply(200).JSON(map[string]interface{}{"errcode": 40003, "errmsg": "invalid openid"})
+
+	_, err := newTestUser().GetBlackList("bad")
+	assert.NotNil(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestBatchBlackList(t *testing.T) {
+	defer gock.Off()
+	openIDs := make([]string, 21)
+	for i := range openIDs {
+		openIDs[i] = fmt.Sprintf("openid%02d", i)
+	}
+	first, _ := json.Marshal(map[string]interface{}{"openid_list": openIDs[:20]})
+	second, _ := json.Marshal(map[string]interface{}{"openid_list": openIDs[20:]})
+	//超过20个openid时分批请求
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/batchblacklist").
+		AddMatcher(matchBody(t, string(first))).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/batchblacklist").
+		AddMatcher(matchBody(t, string(second))).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/batchunblacklist").
+		AddMatcher(matchBody(t, `{"openid_list":["openid00"]}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+
+	user := newTestUser()
+	assert.Nil(t, user.BatchBlackList(openIDs...))
+	assert.Nil(t, user.BatchUnBlackList(openIDs[0]))
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,112 @@
+package user
+
+import "github.com/silenceper/wechat/v2/util"
+
+// pageFetcher 按游标拉取一页openid，返回本页的openid、下一页游标以及总数
+type pageFetcher func(next string) (openIDs []string, nextOpenID string, total int, err error)
+
+// OpenIDIterator 按需分页拉取openid的迭代器，不会一次性将全部openid加载到内存
+//
+//	it := user.IterateUserOpenIDs("")
+//	for it.Next() {
+//		fmt.Println(it.OpenID())
+//	}
+//	if err := it.Err(); err != nil {
+//		//可以使用 it.Checkpoint() 从中断处恢复
+//	}
+type OpenIDIterator struct {
+	fetch pageFetcher
+
+	buf        []string
+	current    string
+	checkpoint string
+	next       string
+	total      int
+	fetched    int
+	done       bool
+	err        error
+}
+
+func newOpenIDIterator(startOpenID string, fetch pageFetcher) *OpenIDIterator {
+	return &OpenIDIterator{
+		fetch:      fetch,
+		next:       startOpenID,
+		checkpoint: startOpenID,
+	}
+}
+
+// Next 移动到下一个openid，没有更多数据或出错时返回false
+func (it *OpenIDIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetchPage()
+	}
+	if it.current != "" {
+		it.checkpoint = it.current
+	}
+	it.current = it.buf[0]
+	it.buf = it.buf[1:]
+	it.fetched++
+	return true
+}
+
+func (it *OpenIDIterator) fetchPage() {
+	openIDs, nextOpenID, total, err := it.fetch(it.next)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.total = total
+	it.buf = openIDs
+	if len(openIDs) == 0 || nextOpenID == "" || nextOpenID == it.next {
+		it.done = true
+	}
+	it.next = nextOpenID
+}
+
+// OpenID 当前的openid
+func (it *OpenIDIterator) OpenID() string {
+	return it.current
+}
+
+// Err 迭代过程中的错误
+func (it *OpenIDIterator) Err() error {
+	return it.err
+}
+
+// Total 接口返回的总数，在第一次调用 Next 之后有效
+func (it *OpenIDIterator) Total() int {
+	return it.total
+}
+
+// Fetched 已经迭代过的openid数量
+func (it *OpenIDIterator) Fetched() int {
+	return it.fetched
+}
+
+// Checkpoint 返回可用于恢复迭代的游标：以此作为起始openid重新迭代，会从当前openid（含）继续
+// 在当前openid处理完成后，可直接使用 OpenID() 作为游标从下一个继续
+func (it *OpenIDIterator) Checkpoint() string {
+	return it.checkpoint
+}
+
+// IterateUserOpenIDs 逐个迭代关注者openid，startOpenID 为空时从头开始
+// 与 ListUserOpenIDs 不同，接口返回errcode时迭代以错误结束
+func (user *User) IterateUserOpenIDs(startOpenID string) *OpenIDIterator {
+	return newOpenIDIterator(startOpenID, func(next string) ([]string, string, int, error) {
+		response, err := user.getUserList(next)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		var list struct {
+			util.CommonError
+			OpenidList
+		}
+		if err = util.DecodeWithError(response, &list, "ListUserOpenIDs"); err != nil {
+			return nil, "", 0, err
+		}
+		return list.Data.OpenIDs, list.NextOpenID, list.Total, nil
+	})
+}
@@ -0,0 +1,86 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func fakeFetcher(pages map[string][]string, fail string) pageFetcher {
+	return func(next string) ([]string, string, int, error) {
+		if next == fail {
+			return nil, "", 0, errors.New("fetch failed")
+		}
+		ids := pages[next]
+		if len(ids) == 0 {
+			return nil, "", 5, nil
+		}
+		return ids, ids[len(ids)-1], 5, nil
+	}
+}
+
+func TestOpenIDIterator(t *testing.T) {
+	pages := map[string][]string{
+		"":  {"a", "b"},
+		"b": {"c", "d"},
+		"d": {"e"},
+		"e": nil,
+	}
+	it := newOpenIDIterator("", fakeFetcher(pages, "-"))
+	var got []string
+	for it.Next() {
+		got = append(got, it.OpenID())
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, got)
+	assert.Equal(t, 5, it.Total())
+	assert.Equal(t, 5, it.Fetched())
+}
+
+func TestOpenIDIteratorCheckpoint(t *testing.T) {
+	pages := map[string][]string{
+		"":  {"a", "b"},
+		"b": {"c", "d"},
+	}
+	it := newOpenIDIterator("", fakeFetcher(pages, "d"))
+	var got []string
+	for it.Next() {
+		got = append(got, it.OpenID())
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, got)
+	assert.NotNil(t, it.Err())
+	assert.Equal(t, "c", it.Checkpoint())
+
+	//从检查点恢复时会重新返回最后一个openid
+	pages["c"] = []string{"d"}
+	it = newOpenIDIterator(it.Checkpoint(), fakeFetcher(pages, "-"))
+	assert.True(t, it.Next())
+	assert.Equal(t, "d", it.OpenID())
+	assert.Equal(t, "c", it.Checkpoint())
+}
+
+func TestIterateUserOpenIDs(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Get("/cgi-bin/user/get").
+		MatchParam("access_token", "mock-ak").
+		Reply(200).JSON(map[string]interface{}{
+		"total": 3, "count": 2, "data": map[string]interface{}{"openid": []string{"a", "b"}}, "next_openid": "b",
+	})
+	gock.New("https://api.weixin.qq.com").Get("/cgi-bin/user/get").
+		MatchParam("next_openid", "b").
+		Reply(200).JSON(map[string]interface{}{"errcode": 45009, "errmsg": "api freq out of limit"})
+
+	user := NewUser(&context.Context{AccessTokenHandle: mockAccessToken{}})
+	it := user.IterateUserOpenIDs("")
+	var got []string
+	for it.Next() {
+		got = append(got, it.OpenID())
+	}
+	assert.Equal(t, []string{"a", "b"}, got)
+	assert.NotNil(t, it.Err())
+	assert.Equal(t, 3, it.Total())
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,158 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const changeOpenIDURL = "https://api.weixin.qq.com/cgi-bin/changeopenid?access_token=%s"
+
+const (
+	// changeOpenIDLimit 每次转换openid的最大数量
+	changeOpenIDLimit = 100
+	// DefaultMigrateInterval 两次转换请求之间的默认间隔
+	DefaultMigrateInterval = 200 * time.Millisecond
+
+	// missingResultErrMsg 转换结果中缺少某个openid时传给 MigrationSink.Failed 的错误信息
+	missingResultErrMsg = "missing in result"
+)
+
+// ChangeOpenIDResult openid转换结果，ErrMsg 不为空时表示该openid转换失败
+type ChangeOpenIDResult struct {
+	OriOpenID string `json:"ori_openid"`
+	NewOpenID string `json:"new_openid"`
+	ErrMsg    string `json:"err_msg,omitempty"`
+}
+
+// OpenIDSource openid数据源，OpenIDIterator 实现了该接口
+type OpenIDSource interface {
+	Next() bool
+	OpenID() string
+	Err() error
+}
+
+// MigrationSink 接收openid迁移结果
+type MigrationSink interface {
+	// Mapped 转换成功的openid
+	Mapped(oriOpenID, newOpenID string) error
+	// Failed 转换失败的openid
+	Failed(oriOpenID, errMsg string) error
+	// Checkpoint 已处理（含跳过）的openid数量，可作为 OpenIDMigrator.Skip 从此处恢复
+	Checkpoint(processed int) error
+}
+
+// ChangeOpenID 公众号迁移主体后将原主体下的openid转换为新主体下的openid，每次最多100个
+// 仅在迁移完成后15天内有效
+func (user *User) ChangeOpenID(fromAppID string, openIDs []string) ([]ChangeOpenIDResult, error) {
+	if len(openIDs) > changeOpenIDLimit {
+		return nil, fmt.Errorf("ChangeOpenID openid_list should not exceed %d", changeOpenIDLimit)
+	}
+	req := map[string]interface{}{
+		"from_appid":  fromAppID,
+		"openid_list": openIDs,
+	}
+	var res struct {
+		util.CommonError
+		ResultList []ChangeOpenIDResult `json:"result_list"`
+	}
+	if err := user.tagRequest(changeOpenIDURL, req, &res, "ChangeOpenID"); err != nil {
+		return nil, err
+	}
+	return res.ResultList, nil
+}
+
+// OpenIDMigrator 批量迁移openid
+type OpenIDMigrator struct {
+	user      *User
+	fromAppID string
+
+	// Interval 两次请求之间的最小间隔，<=0 时使用 DefaultMigrateInterval
+	Interval time.Duration
+	// Skip 跳过数据源中前 Skip 个openid，用于从上一次的 Checkpoint 恢复
+	Skip int
+}
+
+// NewOpenIDMigrator 实例化，fromAppID 为原主体的appid
+func (user *User) NewOpenIDMigrator(fromAppID string) *OpenIDMigrator {
+	return &OpenIDMigrator{
+		user:      user,
+		fromAppID: fromAppID,
+	}
+}
+
+// Run 从 source 中读取openid，按100个一组调用转换接口并将结果写入 sink，返回已处理的数量
+// 出错时可将返回的数量作为 Skip 重新执行
+func (m *OpenIDMigrator) Run(source OpenIDSource, sink MigrationSink) (int, error) {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = DefaultMigrateInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	processed := 0
+	batch := make([]string, 0, changeOpenIDLimit)
+	for source.Next() {
+		if processed < m.Skip {
+			processed++
+			continue
+		}
+		batch = append(batch, source.OpenID())
+		if len(batch) < changeOpenIDLimit {
+			continue
+		}
+		if err := m.migrate(batch, sink, ticker, processed == m.Skip); err != nil {
+			return processed, err
+		}
+		processed += len(batch)
+		batch = batch[:0]
+		if err := sink.Checkpoint(processed); err != nil {
+			return processed, err
+		}
+	}
+	if err := source.Err(); err != nil {
+		return processed, err
+	}
+	if len(batch) == 0 {
+		return processed, nil
+	}
+	if err := m.migrate(batch, sink, ticker, processed == m.Skip); err != nil {
+		return processed, err
+	}
+	processed += len(batch)
+	return processed, sink.Checkpoint(processed)
+}
+
+func (m *OpenIDMigrator) migrate(openIDs []string, sink MigrationSink, ticker *time.Ticker, first bool) error {
+	if !first {
+		<-ticker.C
+	}
+	results, err := m.user.ChangeOpenID(m.fromAppID, openIDs)
+	if err != nil {
+		return err
+	}
+	returned := make(map[string]bool, len(results))
+	for _, res := range results {
+		returned[res.OriOpenID] = true
+		if res.ErrMsg != "" && res.ErrMsg != "ok" {
+			err = sink.Failed(res.OriOpenID, res.ErrMsg)
+		} else {
+			err = sink.Mapped(res.OriOpenID, res.NewOpenID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	//微信未返回结果的openid同样记为失败，否则在 Checkpoint 之后将无从得知
+	for _, openID := range openIDs {
+		if returned[openID] {
+			continue
+		}
+		if err = sink.Failed(openID, missingResultErrMsg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mockAccessToken struct{}
+
+func (mockAccessToken) GetAccessToken() (string, error) {
+	return "mock-ak", nil
+}
+
+type sliceSource struct {
+	openIDs []string
+	index   int
+}
+
+func (source *sliceSource) Next() bool {
+	source.index++
+	return source.index <= len(source.openIDs)
+}
+
+func (source *sliceSource) OpenID() string {
+	return source.openIDs[source.index-1]
+}
+
+func (source *sliceSource) Err() error {
+	return nil
+}
+
+type recordSink struct {
+	mapped     map[string]string
+	failed     map[string]string
+	checkpoint int
+}
+
+func (sink *recordSink) Mapped(oriOpenID, newOpenID string) error {
+	sink.mapped[oriOpenID] = newOpenID
+	return nil
+}
+
+func (sink *recordSink) Failed(oriOpenID, errMsg string) error {
+	sink.failed[oriOpenID] = errMsg
+	return nil
+}
+
+func (sink *recordSink) Checkpoint(processed int) error {
+	sink.checkpoint = processed
+	return nil
+}
+
+func TestOpenIDMigratorMissingResult(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/changeopenid").
+		Reply(200).JSON(map[string]interface{}{
+		"errcode": 0,
+		"errmsg":  "ok",
+		"result_list": []map[string]string{
+			{"ori_openid": "a", "new_openid": "new-a", "err_msg": "ok"},
+			{"ori_openid": "b", "err_msg": "ori_openid error"},
+		},
+	})
+
+	migrator := NewUser(&context.Context{AccessTokenHandle: mockAccessToken{}}).NewOpenIDMigrator("wx-from")
+	migrator.Interval = time.Millisecond
+	sink := &recordSink{mapped: map[string]string{}, failed: map[string]string{}}
+	processed, err := migrator.Run(&sliceSource{openIDs: []string{"a", "b", "c"}}, sink)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, processed)
+	assert.Equal(t, 3, sink.checkpoint)
+	assert.Equal(t, map[string]string{"a": "new-a"}, sink.mapped)
+	assert.Equal(t, map[string]string{"b": "ori_openid error", "c": missingResultErrMsg}, sink.failed)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,180 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/silenceper/wechat/v2/util"
+)
+
+const (
+	tagCreateURL         = "https://api.weixin.qq.com/cgi-bin/tags/create?access_token=%s"
+	tagGetURL            = "https://api.weixin.qq.com/cgi-bin/tags/get?access_token=%s"
+	tagUpdateURL         = "https://api.weixin.qq.com/cgi-bin/tags/update?access_token=%s"
+	tagDeleteURL         = "https://api.weixin.qq.com/cgi-bin/tags/delete?access_token=%s"
+	tagUserListURL       = "https://api.weixin.qq.com/cgi-bin/user/tag/get?access_token=%s"
+	tagBatchTaggingURL   = "https://api.weixin.qq.com/cgi-bin/tags/members/batchtagging?access_token=%s"
+	tagBatchUntaggingURL = "https://api.weixin.qq.com/cgi-bin/tags/members/batchuntagging?access_token=%s"
+	tagUserTagsURL       = "https://api.weixin.qq.com/cgi-bin/tags/getidlist?access_token=%s"
+)
+
+// batchTaggingLimit 批量为用户打标签/取消标签时每次最多的openid数量
+const batchTaggingLimit = 50
+
+// Tag 用户标签
+type Tag struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count,omitempty"`
+}
+
+// TagOpenIDList 标签下的粉丝列表
+type TagOpenIDList struct {
+	Count int `json:"count"`
+	Data  struct {
+		OpenIDs []string `json:"openid"`
+	} `json:"data"`
+	NextOpenID string `json:"next_openid"`
+}
+
+// CreateTag 创建标签，返回标签ID
+func (user *User) CreateTag(name string) (*Tag, error) {
+	req := map[string]map[string]string{
+		"tag": {"name": name},
+	}
+	var res struct {
+		util.CommonError
+		Tag *Tag `json:"tag"`
+	}
+	if err := user.tagRequest(tagCreateURL, req, &res, "CreateTag"); err != nil {
+		return nil, err
+	}
+	return res.Tag, nil
+}
+
+// GetTag 获取公众号已创建的标签
+func (user *User) GetTag() ([]*Tag, error) {
+	accessToken, err := user.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	response, err := util.HTTPGet(fmt.Sprintf(tagGetURL, accessToken))
+	if err != nil {
+		return nil, err
+	}
+	var res struct {
+		util.CommonError
+		Tags []*Tag `json:"tags"`
+	}
+	if err = util.DecodeWithError(response, &res, "GetTag"); err != nil {
+		return nil, err
+	}
+	return res.Tags, nil
+}
+
+// UpdateTag 编辑标签
+func (user *User) UpdateTag(tagID int64, name string) error {
+	req := map[string]map[string]interface{}{
+		"tag": {"id": tagID, "name": name},
+	}
+	var res struct{ util.CommonError }
+	return user.tagRequest(tagUpdateURL, req, &res, "UpdateTag")
+}
+
+// DeleteTag 删除标签，标签下粉丝数超过10w时需先取消粉丝的标签
+func (user *User) DeleteTag(tagID int64) error {
+	req := map[string]map[string]int64{
+		"tag": {"id": tagID},
+	}
+	var res struct{ util.CommonError }
+	return user.tagRequest(tagDeleteURL, req, &res, "DeleteTag")
+}
+
+// OpenIDListByTag 获取标签下粉丝列表，每次最多拉取10000个，nextOpenID 为空时从头开始拉取
+func (user *User) OpenIDListByTag(tagID int64, nextOpenID ...string) (*TagOpenIDList, error) {
+	req := map[string]interface{}{
+		"tagid": tagID,
+	}
+	if len(nextOpenID) > 0 && nextOpenID[0] != "" {
+		req["next_openid"] = nextOpenID[0]
+	}
+	var res struct {
+		util.CommonError
+		TagOpenIDList
+	}
+	if err := user.tagRequest(tagUserListURL, req, &res, "OpenIDListByTag"); err != nil {
+		return nil, err
+	}
+	return &res.TagOpenIDList, nil
+}
+
+// ListAllOpenIDsByTag 获取标签下的全部粉丝openid
+func (user *User) ListAllOpenIDsByTag(tagID int64) ([]string, error) {
+	openIDs := make([]string, 0)
+	nextOpenID := ""
+	for {
+		list, err := user.OpenIDListByTag(tagID, nextOpenID)
+		if err != nil {
+			return nil, err
+		}
+		openIDs = append(openIDs, list.Data.OpenIDs...)
+		if list.Count == 0 || list.NextOpenID == "" || list.NextOpenID == nextOpenID {
+			return openIDs, nil
+		}
+		nextOpenID = list.NextOpenID
+	}
+}
+
+// BatchTag 批量为用户打标签，openIDs 超过50个时自动分批请求
+func (user *User) BatchTag(openIDs []string, tagID int64) error {
+	return user.batchTagging(tagBatchTaggingURL, openIDs, tagID, "BatchTag")
+}
+
+// BatchUntag 批量为用户取消标签，openIDs 超过50个时自动分批请求
+func (user *User) BatchUntag(openIDs []string, tagID int64) error {
+	return user.batchTagging(tagBatchUntaggingURL, openIDs, tagID, "BatchUntag")
+}
+
+// UserTidList 获取用户身上的标签列表
+func (user *User) UserTidList(openID string) ([]int64, error) {
+	req := map[string]string{
+		"openid": openID,
+	}
+	var res struct {
+		util.CommonError
+		TagIDList []int64 `json:"tagid_list"`
+	}
+	if err := user.tagRequest(tagUserTagsURL, req, &res, "UserTidList"); err != nil {
+		return nil, err
+	}
+	return res.TagIDList, nil
+}
+
+func (user *User) batchTagging(apiURL string, openIDs []string, tagID int64, apiName string) error {
+	for start := 0; start < len(openIDs); start += batchTaggingLimit {
+		end := start + batchTaggingLimit
+		if end > len(openIDs) {
+			end = len(openIDs)
+		}
+		req := map[string]interface{}{
+			"openid_list": openIDs[start:end],
+			"tagid":       tagID,
+		}
+		var res struct{ util.CommonError }
+		if err := user.tagRequest(apiURL, req, &res, apiName); err != nil {
+			return fmt.Errorf("%v, openid offset=%d", err, start)
+		}
+	}
+	return nil
+}
+
+func (user *User) tagRequest(apiURL string, req, res interface{}, apiName string) error {
+	accessToken, err := user.GetAccessToken()
+	if err != nil {
+		return err
+	}
+	response, err := util.PostJSON(fmt.Sprintf(apiURL, accessToken), req)
+	if err != nil {
+		return err
+	}
+	return util.DecodeWithError(response, res, apiName)
+}
@@ -0,0 +1,106 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/silenceper/wechat/v2/officialaccount/context"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// matchBody 校验请求体的JSON内容
+func matchBody(t *testing.T, expected string) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		var got, want interface{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal([]byte(expected), &want); err != nil {
+			return false, err
+		}
+		return assert.Equal(t, want, got), nil
+	}
+}
+
+func newTestUser() *User {
+	return NewUser(&context.Context{AccessTokenHandle: mockAccessToken{}})
+}
+
+func TestTag(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/create").MatchParam("access_token", "mock-ak").
+		AddMatcher(matchBody(t, `{"tag":{"name":"广东"}}`)).
+		Reply(200).JSON(map[string]interface{}{"tag": map[string]interface{}{"id": 134, "name": "广东"}})
+	gock.New("https://api.weixin.qq.com").Get("/cgi-bin/tags/get").
+		Reply(200).JSON(map[string]interface{}{"tags": []map[string]interface{}{{"id": 134, "name": "广东", "count": 2}}})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/update").
+		AddMatcher(matchBody(t, `{"tag":{"id":134,"name":"广东人"}}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/delete").
+		AddMatcher(matchBody(t, `{"tag":{"id":134}}`)).
+		Reply(200).JSON(map[string]interface{}{"errcode": 45058, "errmsg": "can't modify sys tag"})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/getidlist").
+		AddMatcher(matchBody(t, `{"openid":"openid1"}`)).
+		Reply(200).JSON(map[string]interface{}{"tagid_list": []int64{134, 2}})
+
+	user := newTestUser()
+	tag, err := user.CreateTag("广东")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(134), tag.ID)
+
+	tags, err := user.GetTag()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), tags[0].Count)
+
+	assert.Nil(t, user.UpdateTag(134, "广东人"))
+	assert.NotNil(t, user.DeleteTag(134))
+
+	tagIDs, err := user.UserTidList("openid1")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{134, 2}, tagIDs)
+	assert.True(t, gock.IsDone())
+}
+
+func TestListAllOpenIDsByTag(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/user/tag/get").
+		AddMatcher(matchBody(t, `{"tagid":134}`)).
+		Reply(200).JSON(map[string]interface{}{"count": 2, "data": map[string]interface{}{"openid": []string{"a", "b"}}, "next_openid": "b"})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/user/tag/get").
+		AddMatcher(matchBody(t, `{"tagid":134,"next_openid":"b"}`)).
+		Reply(200).JSON(map[string]interface{}{"count": 0, "next_openid": ""})
+
+	openIDs, err := newTestUser().ListAllOpenIDsByTag(134)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, openIDs)
+	assert.True(t, gock.IsDone())
+}
+
+func TestBatchTag(t *testing.T) {
+	defer gock.Off()
+	openIDs := make([]string, 51)
+	for i := range openIDs {
+		openIDs[i] = fmt.Sprintf("openid%02d", i)
+	}
+	first, _ := json.Marshal(map[string]interface{}{"openid_list": openIDs[:50], "tagid": 134})
+	second, _ := json.Marshal(map[string]interface{}{"openid_list": openIDs[50:], "tagid": 134})
+	//超过50个openid时分批请求
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/batchtagging").
+		AddMatcher(matchBody(t, string(first))).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/batchtagging").
+		AddMatcher(matchBody(t, string(second))).
+		Reply(200).JSON(map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+	gock.New("https://api.weixin.qq.com").Post("/cgi-bin/tags/members/batchuntagging").
+		Reply(200).JSON(map[string]interface{}{"errcode": 45059, "errmsg": "has too many tags"})
+
+	user := newTestUser()
+	assert.Nil(t, user.BatchTag(openIDs, 134))
+	err := user.BatchUntag(openIDs[:1], 134)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "openid offset=0")
+	assert.True(t, gock.IsDone())
+}
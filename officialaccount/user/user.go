@@ -106,20 +106,11 @@ func (user *User) UpdateRemark(openID, remark string) (err error) {
 
 // ListUserOpenIDs 返回用户列表
 func (user *User) ListUserOpenIDs(nextOpenid ...string) (*OpenidList, error) {
-	accessToken, err := user.GetAccessToken()
-	if err != nil {
-		return nil, err
-	}
-
-	uri, _ := url.Parse(userListURL)
-	q := uri.Query()
-	q.Set("access_token", accessToken)
-	if len(nextOpenid) > 0 && nextOpenid[0] != "" {
-		q.Set("next_openid", nextOpenid[0])
+	next := ""
+	if len(nextOpenid) > 0 {
+		next = nextOpenid[0]
 	}
-	uri.RawQuery = q.Encode()
-
-	response, err := util.HTTPGet(uri.String())
+	response, err := user.getUserList(next)
 	if err != nil {
 		return nil, err
 	}
@@ -133,6 +124,24 @@ func (user *User) ListUserOpenIDs(nextOpenid ...string) (*OpenidList, error) {
 	return userlist, nil
 }
 
+// getUserList 请求用户列表接口，返回原始响应
+func (user *User) getUserList(nextOpenid string) ([]byte, error) {
+	accessToken, err := user.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	uri, _ := url.Parse(userListURL)
+	q := uri.Query()
+	q.Set("access_token", accessToken)
+	if nextOpenid != "" {
+		q.Set("next_openid", nextOpenid)
+	}
+	uri.RawQuery = q.Encode()
+
+	return util.HTTPGet(uri.String())
+}
+
 // ListAllUserOpenIDs 返回所有用户OpenID列表
 func (user *User) ListAllUserOpenIDs() ([]string, error) {
 	nextOpenid := ""
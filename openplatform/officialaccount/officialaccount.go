@@ -1,9 +1,14 @@
 package officialaccount
 
 import (
+	"time"
+
 	"github.com/silenceper/wechat/v2/credential"
 	"github.com/silenceper/wechat/v2/officialaccount"
 	offConfig "github.com/silenceper/wechat/v2/officialaccount/config"
+	"github.com/silenceper/wechat/v2/officialaccount/conversation"
+	"github.com/silenceper/wechat/v2/officialaccount/message"
+	"github.com/silenceper/wechat/v2/officialaccount/qrlogin"
 	opContext "github.com/silenceper/wechat/v2/openplatform/context"
 )
 
@@ -18,16 +23,38 @@ type OfficialAccount struct {
 //appID :为授权方公众号 APPID，非开放平台第三方平台 APPID
 func NewOfficialAccount(opCtx *opContext.Context, appID string) *OfficialAccount {
 	officialAccount := officialaccount.NewOfficialAccount(&offConfig.Config{
-		AppID:          opCtx.AppID,
-		EncodingAESKey: opCtx.EncodingAESKey,
-		Token:          opCtx.Token,
-		Cache:          opCtx.Cache,
+		AppID:               opCtx.AppID,
+		EncodingAESKey:      opCtx.EncodingAESKey,
+		PrevEncodingAESKeys: opCtx.PrevEncodingAESKeys,
+		Token:               opCtx.Token,
+		Cache:               opCtx.Cache,
 	})
 	//设置获取access_token的函数
 	officialAccount.SetAccessTokenHandle(NewDefaultAuthrAccessToken(opCtx, appID))
 	return &OfficialAccount{appID: appID, OfficialAccount: officialAccount}
 }
 
+//GetConversation 多轮对话会话状态管理，会话按授权方appID隔离
+func (officialAccount *OfficialAccount) GetConversation(ttl time.Duration) *conversation.Conversation {
+	conv := officialAccount.OfficialAccount.GetConversation(ttl)
+	conv.SetAppID(officialAccount.appID)
+	return conv
+}
+
+//GetTemplate 模板消息接口，模板缓存按授权方appID隔离
+func (officialAccount *OfficialAccount) GetTemplate() *message.Template {
+	tpl := officialAccount.OfficialAccount.GetTemplate()
+	tpl.SetAppID(officialAccount.appID)
+	return tpl
+}
+
+//GetQRLogin 扫码登录，登录会话按授权方appID隔离
+func (officialAccount *OfficialAccount) GetQRLogin(expire time.Duration) *qrlogin.QRLogin {
+	q := officialAccount.OfficialAccount.GetQRLogin(expire)
+	q.SetAppID(officialAccount.appID)
+	return q
+}
+
 //DefaultAuthrAccessToken 默认获取授权ak的方法
 type DefaultAuthrAccessToken struct {
 	opCtx *opContext.Context
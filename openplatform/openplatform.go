@@ -3,17 +3,22 @@ package openplatform
 import (
 	"net/http"
 
+	"github.com/silenceper/wechat/v2/credential"
+	"github.com/silenceper/wechat/v2/officialaccount/audit"
 	"github.com/silenceper/wechat/v2/officialaccount/server"
 	"github.com/silenceper/wechat/v2/openplatform/account"
 	"github.com/silenceper/wechat/v2/openplatform/config"
 	"github.com/silenceper/wechat/v2/openplatform/context"
 	"github.com/silenceper/wechat/v2/openplatform/miniprogram"
 	"github.com/silenceper/wechat/v2/openplatform/officialaccount"
+	"github.com/silenceper/wechat/v2/util"
 )
 
 //OpenPlatform 微信开放平台相关api
 type OpenPlatform struct {
 	*context.Context
+	replayChecker *util.ReplayChecker
+	auditSink     audit.Sink
 }
 
 //NewOpenPlatform new openplatform
@@ -24,18 +29,34 @@ func NewOpenPlatform(cfg *config.Config) *OpenPlatform {
 	ctx := &context.Context{
 		Config: cfg,
 	}
-	return &OpenPlatform{ctx}
+	return &OpenPlatform{
+		Context:       ctx,
+		replayChecker: util.NewReplayChecker(cfg.Cache, credential.CacheKeyOfficialAccountPrefix+cfg.AppID, 0),
+	}
+}
+
+//SetAuditSink 设置消息审计，GetServer 以及 GetOfficialAccount 返回的公众号的server均会启用
+func (openPlatform *OpenPlatform) SetAuditSink(sink audit.Sink) {
+	openPlatform.auditSink = sink
+}
+
+//SetReplayChecker 设置消息回调的防重放校验，GetServer 以及 GetOfficialAccount 返回的公众号的server均会启用
+//默认已启用窗口为 util.DefaultReplayWindow 的校验，传nil时关闭
+func (openPlatform *OpenPlatform) SetReplayChecker(checker *util.ReplayChecker) {
+	openPlatform.replayChecker = checker
 }
 
 //GetServer get server
 func (openPlatform *OpenPlatform) GetServer(req *http.Request, writer http.ResponseWriter) *server.Server {
-	off := officialaccount.NewOfficialAccount(openPlatform.Context, "")
-	return off.GetServer(req, writer)
+	return openPlatform.GetOfficialAccount("").GetServer(req, writer)
 }
 
-//GetOfficialAccount 公众号代处理
+//GetOfficialAccount 公众号代处理，继承开放平台设置的消息审计与防重放校验
 func (openPlatform *OpenPlatform) GetOfficialAccount(appID string) *officialaccount.OfficialAccount {
-	return officialaccount.NewOfficialAccount(openPlatform.Context, appID)
+	off := officialaccount.NewOfficialAccount(openPlatform.Context, appID)
+	off.SetReplayChecker(openPlatform.replayChecker)
+	off.SetAuditSink(openPlatform.auditSink)
+	return off
 }
 
 //GetMiniProgram 小程序代理
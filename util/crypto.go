@@ -105,6 +105,27 @@ func DecryptMsg(appID, encryptedMsg, aesKey string) (random, rawMsgXMLBytes []by
 	return
 }
 
+//DecryptMsgWithKeys 依次使用多个EncodingAESKey尝试解密消息，用于EncodingAESKey轮换期间
+//返回解密成功所使用的aesKey，回复消息时应使用该key加密
+func DecryptMsgWithKeys(appID, encryptedMsg string, aesKeys []string) (random, rawMsgXMLBytes []byte, aesKey string, err error) {
+	tried := false
+	for _, key := range aesKeys {
+		if key == "" {
+			continue
+		}
+		tried = true
+		random, rawMsgXMLBytes, err = DecryptMsg(appID, encryptedMsg, key)
+		if err == nil {
+			aesKey = key
+			return
+		}
+	}
+	if !tried {
+		err = fmt.Errorf("encodingAESKey is empty")
+	}
+	return
+}
+
 func aesKeyDecode(encodedAESKey string) (key []byte, err error) {
 	if len(encodedAESKey) != 43 {
 		err = fmt.Errorf("the length of encodedAESKey must be equal to 43")
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecryptMsgWithKeys(t *testing.T) {
+	currentKey := "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	prevKey := "GFEDCBA9876543210zyxwvutsrqponmlkjihgfedcba"
+	random := []byte(RandomStr(16))
+	rawMsg := []byte("<xml><Content>hello</Content></xml>")
+
+	encrypted, err := EncryptMsg(random, rawMsg, "wx-appid", prevKey)
+	assert.Nil(t, err)
+
+	gotRandom, gotMsg, usedKey, err := DecryptMsgWithKeys("wx-appid", string(encrypted), []string{currentKey, prevKey})
+	assert.Nil(t, err)
+	assert.Equal(t, prevKey, usedKey)
+	assert.Equal(t, random, gotRandom)
+	assert.Equal(t, rawMsg, gotMsg)
+
+	_, _, _, err = DecryptMsgWithKeys("wx-appid", string(encrypted), []string{currentKey})
+	assert.NotNil(t, err)
+}
+
+func TestDecryptMsgWithEmptyKeys(t *testing.T) {
+	_, msg, usedKey, err := DecryptMsgWithKeys("wx-appid", "ciphertext", []string{"", ""})
+	assert.NotNil(t, err)
+	assert.Nil(t, msg)
+	assert.Empty(t, usedKey)
+}
+
+func TestParsePlainTextMsgLenOverflow(t *testing.T) {
+	//random(16B) + msg_len(4B)，msg_len 接近uint32上限时 20+msg_len 会溢出
+	plaintext := append(make([]byte, 16), 0xff, 0xff, 0xff, 0xff)
+	plaintext = append(plaintext, bytes.Repeat([]byte{12}, 12)...)
+	crypt := NewWXBizMsgCrypt("token", "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG", "", XmlType)
+	assert.NotPanics(t, func() {
+		_, _, _, _, err := crypt.ParsePlainText(plaintext)
+		assert.NotNil(t, err)
+	})
+}
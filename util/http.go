@@ -106,12 +106,27 @@ func PostFile(fieldname, filename, uri string) ([]byte, error) {
 	return PostMultipartForm(fields, uri)
 }
 
+//PostFileFromReader 上传文件，文件内容从reader中读取，filename 仅作为表单中的文件名
+func PostFileFromReader(fieldname, filename, uri string, reader io.Reader) ([]byte, error) {
+	fields := []MultipartFormField{
+		{
+			IsFile:     true,
+			Fieldname:  fieldname,
+			Filename:   filename,
+			FileReader: reader,
+		},
+	}
+	return PostMultipartForm(fields, uri)
+}
+
 //MultipartFormField 保存文件或其他字段信息
 type MultipartFormField struct {
 	IsFile    bool
 	Fieldname string
 	Value     []byte
 	Filename  string
+	//FileReader 不为空时从中读取文件内容，否则打开 Filename 对应的本地文件
+	FileReader io.Reader
 }
 
 //PostMultipartForm 上传文件或其他多个字段
@@ -127,14 +142,18 @@ func PostMultipartForm(fields []MultipartFormField, uri string) (respBody []byte
 				return
 			}
 
-			fh, e := os.Open(field.Filename)
-			if e != nil {
-				err = fmt.Errorf("error opening file , err=%v", e)
-				return
+			reader := field.FileReader
+			if reader == nil {
+				fh, e := os.Open(field.Filename)
+				if e != nil {
+					err = fmt.Errorf("error opening file , err=%v", e)
+					return
+				}
+				defer fh.Close()
+				reader = fh
 			}
-			defer fh.Close()
 
-			if _, err = io.Copy(fileWriter, fh); err != nil {
+			if _, err = io.Copy(fileWriter, reader); err != nil {
 				return
 			}
 		} else {
@@ -0,0 +1,130 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+)
+
+// DefaultReplayWindow 回调请求timestamp允许的默认偏差
+const DefaultReplayWindow = 5 * time.Minute
+
+var (
+	// ErrTimestampInvalid timestamp 参数缺失或格式错误
+	ErrTimestampInvalid = errors.New("回调请求timestamp不合法")
+	// ErrTimestampExpired timestamp 超出允许的时间窗口
+	ErrTimestampExpired = errors.New("回调请求timestamp已过期")
+	// ErrNonceReplayed nonce 在时间窗口内已被使用
+	ErrNonceReplayed = errors.New("回调请求nonce重复，疑似重放请求")
+)
+
+// ReplayStats 防重放校验统计
+type ReplayStats struct {
+	Checked          int64 `json:"checked"`           //校验总次数
+	Passed           int64 `json:"passed"`            //校验通过次数
+	InvalidTimestamp int64 `json:"invalid_timestamp"` //timestamp不合法被拒绝的次数
+	ExpiredTimestamp int64 `json:"expired_timestamp"` //timestamp过期被拒绝的次数
+	ReplayedNonce    int64 `json:"replayed_nonce"`    //nonce重复被拒绝的次数
+}
+
+// ReplayChecker 回调请求防重放校验：校验timestamp的新鲜度，并借助cache记录已使用的nonce
+type ReplayChecker struct {
+	// stats 通过 atomic 读写，放在第一个字段以保证在32位平台上64位对齐
+	stats ReplayStats
+
+	cache          cache.Cache
+	cacheKeyPrefix string
+	window         time.Duration
+
+	// OnReject 请求被拒绝时的回调，可用于上报监控
+	OnReject func(timestamp, nonce string, reason error)
+
+	lock sync.Mutex
+}
+
+// NewReplayChecker 实例化
+// window 为允许的timestamp偏差，<=0 时使用 DefaultReplayWindow；cache 为nil时只校验timestamp
+func NewReplayChecker(cache cache.Cache, cacheKeyPrefix string, window time.Duration) *ReplayChecker {
+	if window <= 0 {
+		window = DefaultReplayWindow
+	}
+	return &ReplayChecker{
+		cache:          cache,
+		cacheKeyPrefix: cacheKeyPrefix,
+		window:         window,
+	}
+}
+
+// Check 校验timestamp与nonce，应在签名校验通过之后调用
+func (rc *ReplayChecker) Check(timestamp, nonce string) error {
+	atomic.AddInt64(&rc.stats.Checked, 1)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return rc.reject(timestamp, nonce, ErrTimestampInvalid, &rc.stats.InvalidTimestamp)
+	}
+	diff := time.Duration(GetCurrTS()-ts) * time.Second
+	if diff > rc.window || diff < -rc.window {
+		return rc.reject(timestamp, nonce, ErrTimestampExpired, &rc.stats.ExpiredTimestamp)
+	}
+	if rc.cache != nil {
+		replayed, err := rc.markNonce(timestamp, nonce)
+		if err != nil {
+			return err
+		}
+		if replayed {
+			return rc.reject(timestamp, nonce, ErrNonceReplayed, &rc.stats.ReplayedNonce)
+		}
+	}
+	atomic.AddInt64(&rc.stats.Passed, 1)
+	return nil
+}
+
+// markNonce 记录nonce，返回是否已经存在
+// cache 实现了 cache.Adder 时（内置的 Memory、Redis、Memcache 均已实现）使用原子写入，多实例部署共享cache时同样有效；
+// 否则只能先判断再写入，仅在单个进程内加锁，多实例并发收到同一请求时可能都会通过
+func (rc *ReplayChecker) markNonce(timestamp, nonce string) (bool, error) {
+	cacheKey := fmt.Sprintf("%s_replay_nonce_%s_%s", rc.cacheKeyPrefix, timestamp, nonce)
+	//nonce 需保留到对应timestamp超出窗口为止
+	expire := 2 * rc.window
+	if adder, ok := rc.cache.(cache.Adder); ok {
+		added, err := adder.Add(cacheKey, "1", expire)
+		if err != nil {
+			return false, fmt.Errorf("记录nonce失败, err=%v", err)
+		}
+		return !added, nil
+	}
+
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	if rc.cache.IsExist(cacheKey) {
+		return true, nil
+	}
+	if err := rc.cache.Set(cacheKey, "1", expire); err != nil {
+		return false, fmt.Errorf("记录nonce失败, err=%v", err)
+	}
+	return false, nil
+}
+
+func (rc *ReplayChecker) reject(timestamp, nonce string, reason error, counter *int64) error {
+	atomic.AddInt64(counter, 1)
+	if rc.OnReject != nil {
+		rc.OnReject(timestamp, nonce, reason)
+	}
+	return reason
+}
+
+// Stats 返回当前的校验统计
+func (rc *ReplayChecker) Stats() ReplayStats {
+	return ReplayStats{
+		Checked:          atomic.LoadInt64(&rc.stats.Checked),
+		Passed:           atomic.LoadInt64(&rc.stats.Passed),
+		InvalidTimestamp: atomic.LoadInt64(&rc.stats.InvalidTimestamp),
+		ExpiredTimestamp: atomic.LoadInt64(&rc.stats.ExpiredTimestamp),
+		ReplayedNonce:    atomic.LoadInt64(&rc.stats.ReplayedNonce),
+	}
+}
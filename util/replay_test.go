@@ -0,0 +1,43 @@
+package util
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/silenceper/wechat/v2/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayChecker(t *testing.T) {
+	checker := NewReplayChecker(cache.NewMemory(), "test", time.Minute)
+	now := strconv.FormatInt(GetCurrTS(), 10)
+
+	assert.Nil(t, checker.Check(now, "nonce-1"))
+	assert.Equal(t, ErrNonceReplayed, checker.Check(now, "nonce-1"))
+	assert.Nil(t, checker.Check(now, "nonce-2"))
+
+	expired := strconv.FormatInt(GetCurrTS()-120, 10)
+	assert.Equal(t, ErrTimestampExpired, checker.Check(expired, "nonce-3"))
+	assert.Equal(t, ErrTimestampInvalid, checker.Check("abc", "nonce-4"))
+
+	stats := checker.Stats()
+	assert.Equal(t, int64(5), stats.Checked)
+	assert.Equal(t, int64(2), stats.Passed)
+	assert.Equal(t, int64(1), stats.ReplayedNonce)
+	assert.Equal(t, int64(1), stats.ExpiredTimestamp)
+	assert.Equal(t, int64(1), stats.InvalidTimestamp)
+}
+
+// plainCache 未实现 cache.Adder 的缓存
+type plainCache struct {
+	cache.Cache
+}
+
+func TestReplayCheckerWithoutAdder(t *testing.T) {
+	checker := NewReplayChecker(plainCache{cache.NewMemory()}, "test", time.Minute)
+	now := strconv.FormatInt(GetCurrTS(), 10)
+
+	assert.Nil(t, checker.Check(now, "nonce-1"))
+	assert.Equal(t, ErrNonceReplayed, checker.Check(now, "nonce-1"))
+}
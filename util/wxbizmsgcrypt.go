@@ -131,6 +131,14 @@ func (self *WXBizMsgCrypt) pKCS7Unpadding(plaintext []byte, block_size int) ([]b
 		return nil, NewCryptError(DecryptAESError, "pKCS7Unpadding text not a multiple of the block size")
 	}
 	padding_len := int(plaintext[plaintext_len-1])
+	if padding_len < 1 || padding_len > block_size || padding_len > plaintext_len {
+		return nil, NewCryptError(DecryptAESError, "pKCS7Unpadding padding size is not valid")
+	}
+	for _, b := range plaintext[plaintext_len-padding_len:] {
+		if int(b) != padding_len {
+			return nil, NewCryptError(DecryptAESError, "pKCS7Unpadding padding is not valid")
+		}
+	}
 	return plaintext[:plaintext_len-padding_len], nil
 }
 func (self *WXBizMsgCrypt) cbcEncrypter(plaintext string) ([]byte, *CryptError) {
@@ -200,7 +208,8 @@ func (self *WXBizMsgCrypt) ParsePlainText(plaintext []byte) ([]byte, uint32, []b
 	}
 	random := plaintext[:16]
 	msg_len := binary.BigEndian.Uint32(plaintext[16:20])
-	if text_len < (20 + msg_len) {
+	// 使用错误的key解密时msg_len可能是任意值，先与剩余长度比较，避免 20+msg_len 溢出
+	if msg_len > text_len-20 {
 		return nil, 0, nil, nil, NewCryptError(IllegalBuffer, "plain is to small 2")
 	}
 	msg := plaintext[20 : 20+msg_len]
@@ -208,23 +217,31 @@ func (self *WXBizMsgCrypt) ParsePlainText(plaintext []byte) ([]byte, uint32, []b
 	return random, msg_len, msg, receiver_id, nil
 }
 func (self *WXBizMsgCrypt) VerifyURL(msg_signature, timestamp, nonce, echostr string) ([]byte, *CryptError) {
+	msg, receiver_id, err := self.VerifyURLWithReceiverID(msg_signature, timestamp, nonce, echostr)
+	if nil != err {
+		return nil, err
+	}
+	if len(self.receiver_id) > 0 && strings.Compare(string(receiver_id), self.receiver_id) != 0 {
+		return nil, NewCryptError(ValidateCorpidError, "receiver_id is not equil")
+	}
+	return msg, nil
+}
+
+// VerifyURLWithReceiverID 校验并解密echostr，同时返回明文中的receiver_id，由调用方决定如何校验
+func (self *WXBizMsgCrypt) VerifyURLWithReceiverID(msg_signature, timestamp, nonce, echostr string) ([]byte, []byte, *CryptError) {
 	signature := self.calSignature(timestamp, nonce, echostr)
 	if strings.Compare(signature, msg_signature) != 0 {
-		return nil, NewCryptError(ValidateSignatureError, "signature not equal")
+		return nil, nil, NewCryptError(ValidateSignatureError, "signature not equal")
 	}
 	plaintext, err := self.cbcDecrypter(echostr)
 	if nil != err {
-		return nil, err
+		return nil, nil, err
 	}
 	_, _, msg, receiver_id, err := self.ParsePlainText(plaintext)
 	if nil != err {
-		return nil, err
+		return nil, nil, err
 	}
-	if len(self.receiver_id) > 0 && strings.Compare(string(receiver_id), self.receiver_id) != 0 {
-		fmt.Println(string(receiver_id), self.receiver_id, len(receiver_id), len(self.receiver_id))
-		return nil, NewCryptError(ValidateCorpidError, "receiver_id is not equil")
-	}
-	return msg, nil
+	return msg, receiver_id, nil
 }
 func (self *WXBizMsgCrypt) EncryptMsg(reply_msg, timestamp, nonce string) ([]byte, *CryptError) {
 	rand_str := self.randString(16)
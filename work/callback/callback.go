@@ -2,6 +2,7 @@ package callback
 
 import (
 	"bytes"
+	"encoding/xml"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -16,20 +17,36 @@ import (
 type Callback struct {
 	Token  string `json:"token"`
 	AESKey string `json:"aseKey"`
+	// PrevAESKeys 轮换EncodingAESKey期间的历史key，当前key解密失败时依次尝试
+	PrevAESKeys []string `json:"prevAesKeys"`
 	*context.Context
+
+	replayChecker *util.ReplayChecker
 }
 
 // NewCallback 实例
-func NewCallback(context *context.Context, token string, aesKey string) *Callback {
+func NewCallback(context *context.Context, token string, aesKey string, prevAESKeys ...string) *Callback {
 	cb := new(Callback)
 	cb.Context = context
 	cb.Token = token
 	cb.AESKey = aesKey
+	cb.PrevAESKeys = prevAESKeys
 	return cb
 }
 
+// SetReplayChecker 设置防重放校验，校验timestamp新鲜度以及nonce是否重复
+func (cb *Callback) SetReplayChecker(checker *util.ReplayChecker) {
+	cb.replayChecker = checker
+}
+
 // CheckRequest 检查请求是否合法，并解密消息体
 func (cb *Callback) CheckRequest(req *http.Request) (echoData []byte, reqXMLBytes []byte, err error) {
+	echoData, reqXMLBytes, _, err = cb.CheckRequestWithKey(req)
+	return
+}
+
+// CheckRequestWithKey 检查请求是否合法，并解密消息体，同时返回解密成功所使用的aesKey
+func (cb *Callback) CheckRequestWithKey(req *http.Request) (echoData []byte, reqXMLBytes []byte, aesKey string, err error) {
 	// 验证参数是否齐全
 	msgSignature := req.URL.Query().Get("msg_signature")
 	timeStamp := req.URL.Query().Get("timestamp")
@@ -41,15 +58,19 @@ func (cb *Callback) CheckRequest(req *http.Request) (echoData []byte, reqXMLByte
 	echoStr := req.URL.Query().Get("echostr")
 	echoStr, _ = url.QueryUnescape(echoStr)
 
-	wxBizMsgCrypt := util.NewWXBizMsgCrypt(cb.Token, cb.AESKey, "", util.XmlType)
 	if echoStr != "" {
 		// 校验请求是否合法
-		var wxErr *util.CryptError
-		echoData, wxErr = wxBizMsgCrypt.VerifyURL(msgSignature, timeStamp, nonce, echoStr)
-		if wxErr != nil {
-			err = errors.New(wxErr.ErrMsg)
+		echoData, aesKey, err = cb.decrypt(func(crypt *util.WXBizMsgCrypt) ([]byte, *util.CryptError) {
+			msg, receiverID, wxErr := crypt.VerifyURLWithReceiverID(msgSignature, timeStamp, nonce, echoStr)
+			if wxErr == nil && !cb.validReceiverID(receiverID) {
+				wxErr = util.NewCryptError(util.ValidateCorpidError, "receiver_id is invalid")
+			}
+			return msg, wxErr
+		})
+		if err != nil {
 			return
 		}
+		err = cb.checkReplay(timeStamp, nonce)
 		return
 	}
 
@@ -59,13 +80,73 @@ func (cb *Callback) CheckRequest(req *http.Request) (echoData []byte, reqXMLByte
 
 	if len(reqBody) > 0 {
 		// 解密body体
-		var wxErr1 *util.CryptError
-		reqXMLBytes, wxErr1 = wxBizMsgCrypt.DecryptMsg(msgSignature, timeStamp, nonce, reqBody)
-		if wxErr1 != nil {
-			err = errors.New(wxErr1.ErrMsg)
+		reqXMLBytes, aesKey, err = cb.decrypt(func(crypt *util.WXBizMsgCrypt) ([]byte, *util.CryptError) {
+			msg, wxErr := crypt.DecryptMsg(msgSignature, timeStamp, nonce, reqBody)
+			if wxErr == nil && xml.Unmarshal(msg, new(BaseMsg)) != nil {
+				wxErr = util.NewCryptError(util.ParseXmlError, "decrypted msg is not valid xml")
+			}
+			return msg, wxErr
+		})
+		if err != nil {
 			return
 		}
+		err = cb.checkReplay(timeStamp, nonce)
 	}
 
 	return
 }
+
+// validReceiverID 校验echostr明文中的receiver_id，错误的key也可能恰好通过补位校验，
+// 因此配置了CorpID时要求与之相同，否则至少应是由字母数字组成的ID
+func (cb *Callback) validReceiverID(receiverID []byte) bool {
+	if cb.Context != nil && cb.Config != nil && cb.CorpID != "" {
+		return string(receiverID) == cb.CorpID
+	}
+	if len(receiverID) == 0 || len(receiverID) > 64 {
+		return false
+	}
+	for _, c := range receiverID {
+		isAlnum := (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !isAlnum && c != '_' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// decrypt 依次使用当前key与历史key尝试解密
+func (cb *Callback) decrypt(fn func(crypt *util.WXBizMsgCrypt) ([]byte, *util.CryptError)) ([]byte, string, error) {
+	var wxErr *util.CryptError
+	for _, aesKey := range append([]string{cb.AESKey}, cb.PrevAESKeys...) {
+		var data []byte
+		data, wxErr = fn(util.NewWXBizMsgCrypt(cb.Token, aesKey, "", util.XmlType))
+		if wxErr == nil {
+			return data, aesKey, nil
+		}
+		if wxErr.ErrCode == util.ValidateSignatureError {
+			break
+		}
+	}
+	return nil, "", errors.New(wxErr.ErrMsg)
+}
+
+// EncryptReply 加密回复消息，aesKey 应为 CheckRequestWithKey 返回的key
+func (cb *Callback) EncryptReply(aesKey string, replyXML []byte, timeStamp, nonce string) ([]byte, error) {
+	if aesKey == "" {
+		aesKey = cb.AESKey
+	}
+	wxBizMsgCrypt := util.NewWXBizMsgCrypt(cb.Token, aesKey, cb.CorpID, util.XmlType)
+	data, wxErr := wxBizMsgCrypt.EncryptMsg(string(replyXML), timeStamp, nonce)
+	if wxErr != nil {
+		return nil, errors.New(wxErr.ErrMsg)
+	}
+	return data, nil
+}
+
+// checkReplay 签名校验通过后进行防重放校验
+func (cb *Callback) checkReplay(timeStamp, nonce string) error {
+	if cb.replayChecker == nil {
+		return nil
+	}
+	return cb.replayChecker.Check(timeStamp, nonce)
+}
@@ -0,0 +1,20 @@
+package callback
+
+import (
+	"testing"
+
+	"github.com/silenceper/wechat/v2/work/config"
+	"github.com/silenceper/wechat/v2/work/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidReceiverID(t *testing.T) {
+	cb := NewCallback(&context.Context{Config: &config.Config{}}, "token", "aeskey")
+	assert.True(t, cb.validReceiverID([]byte("ww1234567890abcdef")))
+	assert.False(t, cb.validReceiverID(nil))
+	assert.False(t, cb.validReceiverID([]byte{0x8f, 0x01, 'w'}))
+
+	cb.CorpID = "ww1234567890abcdef"
+	assert.True(t, cb.validReceiverID([]byte("ww1234567890abcdef")))
+	assert.False(t, cb.validReceiverID([]byte("wwother")))
+}
@@ -2,6 +2,7 @@ package work
 
 import (
 	"github.com/silenceper/wechat/v2/credential"
+	"github.com/silenceper/wechat/v2/util"
 	"github.com/silenceper/wechat/v2/work/auth"
 	"github.com/silenceper/wechat/v2/work/basic"
 	"github.com/silenceper/wechat/v2/work/callback"
@@ -12,7 +13,8 @@ import (
 
 // Work 企业微信相关API
 type Work struct {
-	ctx *context.Context
+	ctx           *context.Context
+	replayChecker *util.ReplayChecker
 }
 
 // NewWork 实例化企业微信API
@@ -22,7 +24,10 @@ func NewWork(cfg *config.Config) *Work {
 		Config:            cfg,
 		AccessTokenHandle: defaultAkHandle,
 	}
-	return &Work{ctx}
+	return &Work{
+		ctx:           ctx,
+		replayChecker: util.NewReplayChecker(cfg.Cache, credential.CacheKeyWorkPrefix+cfg.CorpID, 0),
+	}
 }
 
 // GetBasic url 相关配置
@@ -35,9 +40,17 @@ func (w *Work) GetContact() *contact.Contact {
 	return contact.NewContact(w.ctx)
 }
 
-// GetCallback 企业微信回调相关
-func (w *Work) GetCallback(token, aesKey string) *callback.Callback {
-	return callback.NewCallback(w.ctx, token, aesKey)
+// SetReplayChecker 设置回调的防重放校验，GetCallback 返回的callback均会启用
+// 默认已启用窗口为 util.DefaultReplayWindow 的校验，传nil时关闭
+func (w *Work) SetReplayChecker(checker *util.ReplayChecker) {
+	w.replayChecker = checker
+}
+
+// GetCallback 企业微信回调相关，prevAESKeys 为轮换EncodingAESKey期间的历史key
+func (w *Work) GetCallback(token, aesKey string, prevAESKeys ...string) *callback.Callback {
+	cb := callback.NewCallback(w.ctx, token, aesKey, prevAESKeys...)
+	cb.SetReplayChecker(w.replayChecker)
+	return cb
 }
 
 // GetAuth 企业微信认证鉴权相关